@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// position is where a value was found in the input, both are counted from 1.
+type position struct {
+	line, field int
+}
+
+// violation is a pair of neighbouring values that are not in order, or that
+// are equal when they should be unique.
+type violation struct {
+	before, after       string
+	beforePos, afterPos position
+	equal               bool
+}
+
+func (v violation) String() string {
+	if v.before == v.after {
+		return fmt.Sprintf("duplicate (line: %d, field: %d): %q is the same as line: %d, field: %d",
+			v.afterPos.line, v.afterPos.field, v.after, v.beforePos.line, v.beforePos.field)
+	}
+	if v.equal {
+		return fmt.Sprintf("duplicate (line: %d, field: %d): %q is equal to %q at line: %d, field: %d",
+			v.afterPos.line, v.afterPos.field, v.after, v.before, v.beforePos.line, v.beforePos.field)
+	}
+	return fmt.Sprintf("out of order (line: %d, field: %d): %q should sort before %q at line: %d, field: %d",
+		v.afterPos.line, v.afterPos.field, v.after, v.before, v.beforePos.line, v.beforePos.field)
+}
+
+// checkInput reads the input and reports the values that are not in order,
+// rather than sorting them. Only the first violation is reported, unless all
+// is set, in which case every violation is written out.
+func checkInput(split bufio.SplitFunc, compare func(a, b string) int, unique, all bool, reader io.Reader, writer writeFn) error {
+	var starts []int
+	items, err := scan(reader, trackLines(split, &starts))
+	if err != nil {
+		return err
+	}
+
+	// Fields are counted from the first value that starts on the same line.
+	positions := make([]position, len(items))
+	first := 0
+	for i := range items {
+		if i > 0 && starts[i] != starts[i-1] {
+			first = i
+		}
+		positions[i] = position{line: starts[i], field: i - first + 1}
+	}
+
+	violations := checkSorted(items, positions, compare, unique, all)
+	if len(violations) == 0 {
+		return nil
+	}
+	if !all {
+		return errors.New(violations[0].String())
+	}
+
+	res := make([]string, len(violations))
+	for i, v := range violations {
+		res[i] = v.String()
+	}
+	if err := writer(lines(res)); err != nil {
+		return err
+	}
+	return errors.Errorf("not sorted (violations: %d)", len(violations))
+}
+
+// checkSorted compares each value with the one before it, returning the pairs
+// that are out of order. With unique, values that are equal are also
+// reported.
+func checkSorted(items []string, positions []position, compare func(a, b string) int, unique, all bool) []violation {
+	var res []violation
+	for i := 1; i < len(items); i++ {
+		c := compare(items[i-1], items[i])
+		if c < 0 || (c == 0 && !unique) {
+			continue
+		}
+
+		res = append(res, violation{
+			before:    items[i-1],
+			after:     items[i],
+			beforePos: positions[i-1],
+			afterPos:  positions[i],
+			equal:     c == 0,
+		})
+		if !all {
+			break
+		}
+	}
+	return res
+}
+
+// trackLines wraps the split function, recording the line that each token
+// starts on.
+func trackLines(split bufio.SplitFunc, lines *[]int) bufio.SplitFunc {
+	line := 1
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := split(data, atEOF)
+		if token != nil {
+			// Tokens are slices of data, so where they start can be worked
+			// out from how much of data is left after them.
+			start := cap(data) - cap(token)
+			if start < 0 || start > len(data) {
+				start = 0
+			}
+			*lines = append(*lines, line+bytes.Count(data[:start], newline))
+		}
+		if advance > 0 {
+			line += bytes.Count(data[:advance], newline)
+		}
+		return advance, token, err
+	}
+}
+
+var newline = []byte{'\n'}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+func TestCheckInput(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		unique   bool
+		all      bool
+		err      string
+		expected string
+	}{
+		{
+			name:  "sorted",
+			input: "a1 a2 a10\na10 b",
+		},
+		{
+			name:  "first",
+			input: "a1 a10\na9 a2 a3",
+			err:   `out of order (line: 2, field: 1): "a9" should sort before "a10" at line: 1, field: 2`,
+		},
+		{
+			name:     "all",
+			input:    "a1 a10\na9 a2 a3",
+			all:      true,
+			err:      "not sorted (violations: 2)",
+			expected: "out of order (line: 2, field: 1): \"a9\" should sort before \"a10\" at line: 1, field: 2\nout of order (line: 2, field: 2): \"a2\" should sort before \"a9\" at line: 2, field: 1",
+		},
+		{
+			name:   "unique",
+			input:  "a1 a2 a2",
+			unique: true,
+			err:    `duplicate (line: 1, field: 3): "a2" is the same as line: 1, field: 2`,
+		},
+		{
+			name:   "unique equal",
+			input:  "a1 a2 a02",
+			unique: true,
+			err:    `duplicate (line: 1, field: 3): "a02" is equal to "a2" at line: 1, field: 2`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out string
+			writer := func(buf *bytes.Buffer) error {
+				out = buf.String()
+				return nil
+			}
+
+			sorter := natural.NewSorter(natural.WithZeros(natural.ZerosEqual))
+			err := checkInput(splitOn(" "), sorter.Compare, tc.unique, tc.all, strings.NewReader(tc.input), writer)
+			if tc.err == "" && err != nil {
+				t.Fatal(err)
+			} else if tc.err != "" && (err == nil || err.Error() != tc.err) {
+				t.Fatalf("expected: %q, actual: %v", tc.err, err)
+			}
+			if expected, actual := tc.expected, out; expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
+
+func TestCheckInputLines(t *testing.T) {
+	t.Parallel()
+
+	err := checkInput(scanLines(blankKeep, new(string)), natural.NewSorter().Compare, false, false, strings.NewReader("a1\na10\na9"), nil)
+	if expected := `out of order (line: 3, field: 1): "a9" should sort before "a10" at line: 2, field: 1`; err == nil || err.Error() != expected {
+		t.Errorf("expected: %q, actual: %v", expected, err)
+	}
+}
+
+func TestTrackLines(t *testing.T) {
+	t.Parallel()
+
+	var starts []int
+	items, err := scan(strings.NewReader("one two\nthree\n\nfour five"), trackLines(splitOn(" "), &starts))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := []string{"one", "two", "three", "four", "five"}, items; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := []int{1, 1, 2, 4, 4}, starts; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
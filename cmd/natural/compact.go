@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+const (
+	defaultCompactSort   = false
+	defaultCompactExpand = false
+)
+
+// runCompact shortens the input into range notation, or expands it back
+func runCompact(args []string) error {
+	// flags for the compact command
+	var (
+		flagset = flag.NewFlagSet("compact", flag.ExitOnError)
+		flags   = newIOFlags(flagset)
+
+		sort   = flagset.Bool("sort", defaultCompactSort, "natural sort the values before compacting, or after expanding")
+		expand = flagset.Bool("expand", defaultCompactExpand, "expand range notation back into the values")
+	)
+	flagset.Usage = usageFor(flagset, "compact [flags]")
+	if err := flagset.Parse(args); err != nil {
+		return err
+	}
+
+	splitFn, err := flags.validate()
+	if err != nil {
+		return errorFor(flagset, "compact [flags]", err)
+	}
+
+	return flags.run(func(reader io.Reader, writer writeFn) error {
+		items, err := scan(reader, splitFn)
+		if err != nil {
+			return err
+		}
+
+		if *expand {
+			// The separator can split inside brackets, so join the values
+			// back up and let Expand split them outside of the brackets.
+			items, err = natural.Expand(strings.Join(items, ","))
+			if err != nil {
+				return err
+			}
+			if *sort {
+				natural.Sort(items)
+			}
+			return writer(bytes.NewBufferString(flags.join(items)))
+		}
+
+		if *sort {
+			natural.Sort(items)
+		}
+
+		out := flags.join(natural.Compact(items))
+		return writer(bytes.NewBufferString(out))
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultCompareExplain = false
+)
+
+// runCompare compares two strings, optionally explaining the result
+func runCompare(args []string) error {
+	// flags for the compare command
+	var (
+		flagset = flag.NewFlagSet("compare", flag.ExitOnError)
+
+		explain = flagset.Bool("explain", defaultCompareExplain, "show the segments of both strings and the rule that decided the result")
+		units   = flagset.Bool("units", defaultUnits, "compare sizes and durations by magnitude")
+		ip      = flagset.Bool("ip", defaultIP, "compare IP addresses and prefixes numerically")
+		zeros   = flagset.String("zeros", defaultZeros, "order of leading zeros (shorter, longer, equal)")
+	)
+	flagset.Usage = usageFor(flagset, "compare [flags] <a> <b>")
+	if err := flagset.Parse(args); err != nil {
+		return err
+	}
+
+	if flagset.NArg() != 2 {
+		return errorFor(flagset, "compare [flags] <a> <b>", errors.Errorf("expected two strings (args: %q)", flagset.Args()))
+	}
+
+	var options []natural.Option
+	if *units {
+		options = append(options, natural.WithUnits())
+	}
+	if *ip {
+		options = append(options, natural.WithIPAddresses())
+	}
+	if policy, ok := natural.ParseZeros(*zeros); !ok {
+		return errorFor(flagset, "compare [flags] <a> <b>", errors.Errorf("invalid zeros (zeros: %q)", *zeros))
+	} else if policy != natural.ZerosShorterFirst {
+		options = append(options, natural.WithZeros(policy))
+	}
+	sorter := natural.NewSorter(options...)
+
+	e := sorter.Explain(flagset.Arg(0), flagset.Arg(1))
+	if !*explain {
+		_, err := fmt.Fprintln(os.Stdout, e.Result)
+		return err
+	}
+	return writeExplanation(os.Stdout, e)
+}
+
+// writeExplanation writes the result, followed by a table of the segments of
+// both strings. The row that decided the result is marked with its rule, for
+// example:
+//
+//	1
+//	segment  part   a       b
+//	0        text   "file"  "file"
+//	0        value  "010"   "10"  <- leading zeros
+//	1        text   ".txt"  ".txt"
+func writeExplanation(w io.Writer, e natural.Explanation) error {
+	if _, err := fmt.Fprintln(w, e.Result); err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintf(writer, "segment\tpart\ta\tb\n")
+
+	for i := 0; i < len(e.A) || i < len(e.B); i++ {
+		row := func(part, a, b string) {
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s", i, part, a, b)
+
+			// Mark the row that decided the result.
+			if isText := e.Rule == natural.RuleText || e.Rule == natural.RuleEmpty; i == e.Index && e.Rule != natural.RuleEqual && isText == (part == "text") {
+				fmt.Fprintf(writer, "\t<- %s", e.Rule)
+			}
+			fmt.Fprintf(writer, "\n")
+		}
+
+		row("text", cell(e.A, i, false), cell(e.B, i, false))
+		if hasValue(e.A, i) || hasValue(e.B, i) {
+			row("value", cell(e.A, i, true), cell(e.B, i, true))
+		}
+	}
+	return writer.Flush()
+}
+
+// cell quotes the text or value of a segment, segments that are missing are
+// shown as `-`.
+func cell(segments []natural.Segment, i int, value bool) string {
+	if i >= len(segments) {
+		return "-"
+	}
+	if value {
+		return strconv.Quote(segments[i].Value)
+	}
+	return strconv.Quote(segments[i].Text)
+}
+
+func hasValue(segments []natural.Segment, i int) bool {
+	return i < len(segments) && segments[i].Value != ""
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+func TestWriteExplanation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{
+			name: "leading zeros",
+			a:    "file010.txt",
+			b:    "file10.txt",
+			expected: "1\n" +
+				"segment  part   a       b\n" +
+				"0        text   \"file\"  \"file\"\n" +
+				"0        value  \"010\"   \"10\"  <- leading zeros\n" +
+				"1        text   \".txt\"  \".txt\"\n",
+		},
+		{
+			name: "text",
+			a:    "b1",
+			b:    "a22",
+			expected: "1\n" +
+				"segment  part   a    b\n" +
+				"0        text   \"b\"  \"a\"  <- text\n" +
+				"0        value  \"1\"  \"22\"\n" +
+				"1        text   \"\"   \"\"\n",
+		},
+		{
+			name: "missing segment",
+			a:    "a",
+			b:    "a1",
+			expected: "1\n" +
+				"segment  part   a    b\n" +
+				"0        text   \"a\"  \"a\"\n" +
+				"0        value  \"\"   \"1\"  <- missing value\n" +
+				"1        text   -    \"\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeExplanation(&buf, natural.NewSorter().Explain(tc.a, tc.b)); err != nil {
+				t.Fatal(err)
+			}
+			if expected, actual := tc.expected, buf.String(); expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SimonRichardson/naturalsort/pkg/fs"
+	"github.com/SimonRichardson/naturalsort/pkg/group"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSeparator    = ","
+	defaultInputGzip    = false
+	defaultInputBase64  = false
+	defaultOutputGzip   = false
+	defaultOutputBase64 = false
+
+	defaultLines            = false
+	defaultLinesBlank       = blankKeep
+	defaultLinesKeepEnding  = false
+	defaultOutputTerminator = `\n`
+	defaultOutputQuote      = quoteNone
+
+	// stdinFile is the input.file that reads from stdin.
+	stdinFile = "-"
+)
+
+// stdin is where input is read from when input.file is `-`.
+var stdin io.Reader = os.Stdin
+
+// ioFlags are the flags shared by every mode that reads a list of values and
+// writes out a result.
+type ioFlags struct {
+	debug          *bool
+	separator      *string
+	separatorRegex *string
+	extract        *string
+
+	lines           *bool
+	linesBlank      *string
+	linesKeepEnding *bool
+	// ending is the first line ending found in the input, when reading lines.
+	ending *string
+
+	input       *string
+	inputFile   *string
+	inputGzip   *bool
+	inputBase64 *bool
+	inputFormat *string
+
+	outputFile       *string
+	outputGzip       *bool
+	outputBase64     *bool
+	outputSeparator  *string
+	outputTerminator *string
+	outputPrefix     *string
+	outputSuffix     *string
+	outputQuote      *string
+	outputFormat     *string
+	// values are the JSON values read, when reading JSON.
+	values *jsonValues
+}
+
+func newIOFlags(flagset *flag.FlagSet) ioFlags {
+	return ioFlags{
+		debug:          flagset.Bool("debug", false, "debug logging"),
+		separator:      flagset.String("separator", defaultSeparator, "separation value, which can contain escapes (\\n, \\t, \\0, \\x1f)"),
+		separatorRegex: flagset.String("separator.regex", "", "regular expression to split on, the output is joined with separator"),
+		extract:        flagset.String("extract", "", "regular expression of the values to extract, the output is joined with separator"),
+
+		lines:           flagset.Bool("lines", defaultLines, "split the input into lines, ending in \\n, \\r\\n or \\r"),
+		linesBlank:      flagset.String("lines.blank", defaultLinesBlank, "what to do with blank lines (keep, drop)"),
+		linesKeepEnding: flagset.Bool("lines.keepending", defaultLinesKeepEnding, "write lines with the same line ending as the input"),
+		ending:          new(string),
+
+		input:       flagset.String("input", "", "input for natural sorting"),
+		inputFile:   flagset.String("input.file", "", "file required to perform natural sorting on, - reads from stdin"),
+		inputGzip:   flagset.Bool("input.gzip", defaultInputGzip, "decode gzip input"),
+		inputBase64: flagset.Bool("input.base64", defaultInputBase64, "decode base 64 input"),
+		inputFormat: flagset.String("input.format", "", "read the values as a JSON array or JSON Lines, rather than splitting them (json, jsonl)"),
+
+		outputFile:       flagset.String("output.file", "", "output file for action performed"),
+		outputGzip:       flagset.Bool("output.gzip", defaultOutputGzip, "encode gzip output"),
+		outputBase64:     flagset.Bool("output.base64", defaultOutputBase64, "encode base64 output"),
+		outputSeparator:  flagset.String("output.separator", "", "separation value for the output, defaults to separator"),
+		outputTerminator: flagset.String("output.terminator", defaultOutputTerminator, "value written after the output"),
+		outputPrefix:     flagset.String("output.prefix", "", "value written before each output value"),
+		outputSuffix:     flagset.String("output.suffix", "", "value written after each output value"),
+		outputQuote:      flagset.String("output.quote", defaultOutputQuote, "quote each output value (none, single, double)"),
+		outputFormat:     flagset.String("output.format", "", "write the values as a JSON array or JSON Lines (json, jsonl)"),
+		values:           newJSONValues(),
+	}
+}
+
+// validate checks that the flags are valid, returning how to split the
+// input.
+func (f ioFlags) validate() (bufio.SplitFunc, error) {
+	// Validate the separator
+	sep, err := f.sep()
+	if err != nil {
+		return nil, err
+	}
+	if sep == "" {
+		return nil, errors.Errorf("no valid separator (separator: %q)", *f.separator)
+	}
+
+	// Validate how to write the output.
+	if _, err := f.output(); err != nil {
+		return nil, err
+	}
+
+	// Work out how to split the input.
+	split := splitOn(sep)
+	if *f.separatorRegex != "" && *f.extract != "" {
+		return nil, errors.Errorf("only one of separator.regex and extract can be used (separator.regex: %q, extract: %q)", *f.separatorRegex, *f.extract)
+	}
+	if pattern := *f.separatorRegex; pattern != "" {
+		re, err := compileSplit(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid separator.regex (separator.regex: %q)", pattern)
+		}
+		split = splitOnRegex(re)
+	}
+	if pattern := *f.extract; pattern != "" {
+		re, err := compileSplit(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid extract (extract: %q)", pattern)
+		}
+		split = extractRegex(re)
+	}
+
+	if *f.lines {
+		if *f.separatorRegex != "" || *f.extract != "" {
+			return nil, errors.Errorf("lines can't be used with separator.regex or extract")
+		}
+		if blank := *f.linesBlank; blank != blankKeep && blank != blankDrop {
+			return nil, errors.Errorf("invalid blank lines (lines.blank: %q)", blank)
+		}
+		split = scanLines(*f.linesBlank, f.ending)
+	}
+
+	switch format := *f.inputFormat; format {
+	case "":
+	case formatJSON, formatJSONL:
+		if *f.lines || *f.separatorRegex != "" || *f.extract != "" {
+			return nil, errors.Errorf("input.format can't be used with lines, separator.regex or extract (input.format: %q)", format)
+		}
+		split = scanJSON(f.values)
+		if format == formatJSONL {
+			split = scanJSONLines(f.values)
+		}
+	default:
+		return nil, errors.Errorf("invalid input format (input.format: %q)", format)
+	}
+
+	// Validate that we either have an input or a input.file. If neither are
+	// valid then fallback to stdin, as long as something is piped into it,
+	// otherwise bail out.
+	in, inf := strings.TrimSpace(*f.input), strings.TrimSpace(*f.inputFile)
+	if in == "" && inf == "" {
+		if !isPiped(os.Stdin) {
+			return nil, errors.Errorf("no valid input (input: %q, file: %q)", in, inf)
+		}
+		*f.inputFile = stdinFile
+	}
+
+	return split, nil
+}
+
+// isPiped returns if the file is not a terminal, such as when input is piped
+// or redirected into it.
+func isPiped(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// sep returns the separator, with any escape sequences replaced.
+func (f ioFlags) sep() (string, error) {
+	return unescape(*f.separator)
+}
+
+// output returns how to write the output, with any escape sequences
+// replaced.
+func (f ioFlags) output() (output, error) {
+	var (
+		res = output{quote: quoters[*f.outputQuote]}
+		err error
+	)
+	if res.quote == nil {
+		return res, errors.Errorf("invalid quote (output.quote: %q)", *f.outputQuote)
+	}
+
+	switch format := *f.outputFormat; format {
+	case "":
+	case formatJSON, formatJSONL:
+		if *f.outputPrefix != "" || *f.outputSuffix != "" || *f.outputQuote != quoteNone {
+			return res, errors.Errorf("output.format can't be used with output.prefix, output.suffix or output.quote (output.format: %q)", format)
+		}
+	default:
+		return res, errors.Errorf("invalid output format (output.format: %q)", format)
+	}
+
+	separator, terminator := *f.outputSeparator, *f.outputTerminator
+	if separator == "" {
+		separator = *f.separator
+		if *f.lines {
+			separator = `\n`
+		}
+	}
+	// Lines are written the same way they were read, unless asked otherwise.
+	if *f.lines && *f.linesKeepEnding && *f.ending != "" {
+		if *f.outputSeparator == "" {
+			separator = *f.ending
+		}
+		if terminator == defaultOutputTerminator {
+			terminator = *f.ending
+		}
+	}
+	for _, v := range []struct {
+		dst *string
+		src string
+	}{
+		{&res.separator, separator},
+		{&res.terminator, terminator},
+		{&res.prefix, *f.outputPrefix},
+		{&res.suffix, *f.outputSuffix},
+	} {
+		if *v.dst, err = unescape(v.src); err != nil {
+			return res, err
+		}
+	}
+
+	// JSON is always written the same way, apart from the terminator.
+	switch *f.outputFormat {
+	case formatJSON:
+		res.separator, res.open, res.close, res.quote = ",", "[", "]", f.values.encode
+	case formatJSONL:
+		res.separator, res.quote = "\n", f.values.encode
+	}
+	return res, nil
+}
+
+// join joins the values ready for writing.
+func (f ioFlags) join(x []string) string {
+	out, _ := f.output()
+	return out.join(x)
+}
+
+// logger yields a logger that respects the debug flag.
+func (f ioFlags) logger() log.Logger {
+	logLevel := level.AllowInfo()
+	if *f.debug {
+		logLevel = level.AllowAll()
+	}
+
+	var logger log.Logger
+	logger = log.NewLogfmtLogger(os.Stdout)
+	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+	logger = level.NewFilter(logger, logLevel)
+	return logger
+}
+
+// run reads the input and hands it to fn, along with where to write the
+// output, in an execution group that can be interrupted.
+func (f ioFlags) run(fn func(io.Reader, writeFn) error) error {
+	logger := f.logger()
+	level.Debug(logger).Log("type", "input", "file", *f.inputFile, "gzip", *f.inputGzip)
+	level.Debug(logger).Log("type", "output", "file", *f.outputFile, "gzip", *f.outputGzip)
+
+	in, inf := strings.TrimSpace(*f.input), strings.TrimSpace(*f.inputFile)
+
+	// Execution group.
+	var g group.Group
+	{
+		// Create the file system
+		fsys := fs.NewRealFilesystem()
+		g.Add(func() error {
+			// Setup how we're going to read and write.
+			reader, err := read(fsys, in, inf, *f.inputGzip, *f.inputBase64)
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+
+			// The output can depend on the input, so wait until writing to
+			// work out how.
+			writer := func(buf *bytes.Buffer) error {
+				out, _ := f.output()
+				return write(fsys, *f.outputFile, *f.outputGzip, *f.outputBase64, out.terminator)(buf)
+			}
+
+			return fn(reader, writer)
+		}, func(error) {
+			// Nothing to close
+		})
+	}
+	{
+		// Setup os signal interruptions.
+		cancel := make(chan struct{})
+		g.Add(func() error {
+			return interrupt(cancel)
+		}, func(error) {
+			close(cancel)
+		})
+	}
+	return g.Run()
+}
+
+// scan reads all the values from the reader.
+func scan(reader io.Reader, split bufio.SplitFunc) ([]string, error) {
+	// Scan everything!
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(split)
+
+	var buf []string
+	for scanner.Scan() {
+		buf = append(buf, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// lines joins the lines into a buffer ready for writing.
+func lines(x []string) *bytes.Buffer {
+	return bytes.NewBufferString(strings.Join(x, "\n"))
+}
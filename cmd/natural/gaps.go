@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+// runGaps reports the numbers missing from sequences in the input
+func runGaps(args []string) error {
+	// flags for the gaps command
+	var (
+		flagset = flag.NewFlagSet("gaps", flag.ExitOnError)
+		flags   = newIOFlags(flagset)
+	)
+	flagset.Usage = usageFor(flagset, "gaps [flags]")
+	if err := flagset.Parse(args); err != nil {
+		return err
+	}
+
+	splitFn, err := flags.validate()
+	if err != nil {
+		return errorFor(flagset, "gaps [flags]", err)
+	}
+
+	return flags.run(func(reader io.Reader, writer writeFn) error {
+		items, err := scan(reader, splitFn)
+		if err != nil {
+			return err
+		}
+
+		return writer(lines(formatGaps(natural.Gaps(items))))
+	})
+}
+
+// formatGaps formats each sequence on its own line, with the number replaced
+// by `{n}`, for example:
+//
+//	frame_{n}.exr	1-2400	missing=17-20,301	duplicates=frame_5.exr,frame_05.exr
+//
+// The missing and duplicates fields are left out when they're empty.
+func formatGaps(seqs []natural.Sequence) []string {
+	res := make([]string, 0, len(seqs))
+	for _, seq := range seqs {
+		fields := []string{
+			seq.Prefix + "{n}" + seq.Suffix,
+			natural.Range{From: seq.First, To: seq.Last}.String(),
+		}
+		if len(seq.Missing) > 0 {
+			missing := make([]string, len(seq.Missing))
+			for i, r := range seq.Missing {
+				missing[i] = r.String()
+			}
+			fields = append(fields, "missing="+strings.Join(missing, ","))
+		}
+		if len(seq.Duplicates) > 0 {
+			fields = append(fields, "duplicates="+strings.Join(seq.Duplicates, ","))
+		}
+
+		res = append(res, strings.Join(fields, "\t"))
+	}
+	return res
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+func TestFormatGaps(t *testing.T) {
+	t.Parallel()
+
+	items := []string{"frame_0005.exr", "frame_0001.exr", "frame_5.exr", "frame_0009.exr", "notes.txt", "take2", "clip1", "clip3", "shot1", "shot2", "shot02"}
+	expected := []string{
+		"clip{n}\t1-3\tmissing=2",
+		"frame_{n}.exr\t1-9\tmissing=2-4,6-8\tduplicates=frame_5.exr,frame_0005.exr",
+		"shot{n}\t1-2\tduplicates=shot2,shot02",
+		"take{n}\t2",
+	}
+
+	if actual := formatGaps(natural.Gaps(items)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
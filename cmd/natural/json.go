@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	formatJSON  = "json"
+	formatJSONL = "jsonl"
+)
+
+// jsonValues records which values were read as numbers or booleans rather
+// than strings, so they can be written back the same way.
+// Note: values with the same text are handed back in the order they were
+// read, which matches the order they're written in as sorting is stable.
+type jsonValues struct {
+	raw map[string][]bool
+}
+
+func newJSONValues() *jsonValues {
+	return &jsonValues{raw: map[string][]bool{}}
+}
+
+func (v *jsonValues) add(s string, raw bool) {
+	v.raw[s] = append(v.raw[s], raw)
+}
+
+// encode encodes the value the way it was read, values that weren't read as
+// JSON are encoded as strings.
+func (v *jsonValues) encode(s string) string {
+	if flags := v.raw[s]; len(flags) > 0 {
+		v.raw[s] = flags[1:]
+		if flags[0] {
+			return s
+		}
+	}
+	return encodeJSON(s)
+}
+
+// scanJSON splits a JSON array into its values, one at a time, so the whole
+// array is never held as a single token.
+// Note: only strings, numbers and booleans can be sorted, numbers and booleans
+// are kept as they were written and recorded in values. Numbers are sorted by
+// that text rather than by their value, so `1e3` sorts before `2`.
+func scanJSON(values *jsonValues) bufio.SplitFunc {
+	var (
+		started, done bool
+		// next is if a value has been read, so a comma or the end comes next.
+		next  bool
+		count int
+	)
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		for i := 0; ; {
+			for i < len(data) && isJSONSpace(data[i]) {
+				i++
+			}
+			if i == len(data) {
+				if atEOF && !done {
+					return 0, nil, errors.New("unexpected end of JSON input")
+				}
+				return i, nil, nil
+			}
+
+			switch c := data[i]; {
+			case done:
+				return 0, nil, errors.Errorf("invalid character %q after JSON array", c)
+			case !started:
+				if c != '[' {
+					return 0, nil, errors.Errorf("invalid character %q, expected a JSON array", c)
+				}
+				started = true
+				i++
+			case c == ']' && (next || count == 0):
+				done = true
+				i++
+			case next:
+				if c != ',' {
+					return 0, nil, errors.Errorf("invalid character %q after JSON value", c)
+				}
+				next = false
+				i++
+			case c == '[' || c == '{':
+				return 0, nil, errors.Errorf("invalid JSON value, only strings, numbers and booleans can be sorted")
+			default:
+				n := jsonValueEnd(data[i:], atEOF)
+				if n < 0 {
+					if atEOF {
+						return 0, nil, errors.New("unexpected end of JSON input")
+					}
+					return i, nil, nil
+				}
+				s, raw, err := decodeJSON(data[i : i+n])
+				if err != nil {
+					return 0, nil, err
+				}
+				values.add(s, raw)
+				next = true
+				count++
+				return i + n, []byte(s), nil
+			}
+		}
+	}
+}
+
+// scanJSONLines splits JSON Lines into their values, one per line. Blank
+// lines are skipped.
+func scanJSONLines(values *jsonValues) bufio.SplitFunc {
+	lines := scanLines(blankDrop, new(string))
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		n, line, err := lines(data, atEOF)
+		if err != nil || line == nil {
+			return n, nil, err
+		}
+		s, raw, err := decodeJSON(bytes.TrimSpace(line))
+		if err != nil {
+			return 0, nil, err
+		}
+		values.add(s, raw)
+		return n, []byte(s), nil
+	}
+}
+
+// jsonValueEnd returns the length of the JSON value at the start of data, or
+// -1 if more data is needed to find the end of it.
+func jsonValueEnd(data []byte, atEOF bool) int {
+	if data[0] == '"' {
+		for i := 1; i < len(data); i++ {
+			switch data[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return -1
+	}
+	for i, c := range data {
+		if c == ',' || c == ']' || isJSONSpace(c) {
+			return i
+		}
+	}
+	if atEOF {
+		return len(data)
+	}
+	return -1
+}
+
+// decodeJSON decodes a single JSON value into the value to sort, returning
+// if it's a number or boolean, which are kept as they were written.
+func decodeJSON(data []byte) (string, bool, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "", false, errors.Wrapf(err, "invalid JSON value (value: %q)", data)
+	}
+	switch x := v.(type) {
+	case string:
+		return x, false, nil
+	case float64, bool:
+		return string(data), true, nil
+	}
+	return "", false, errors.Errorf("invalid JSON value, only strings, numbers and booleans can be sorted (value: %q)", data)
+}
+
+// encodeJSON encodes the value as a JSON string, leaving HTML characters as
+// they are.
+func encodeJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return string(bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/SimonRichardson/naturalsort/pkg/fs"
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+func TestScanJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, input string
+		split       func(*jsonValues) bufio.SplitFunc
+		expected    []string
+	}{
+		{"empty array", "[]", scanJSON, nil},
+		{"array", ` [ "b10", "b9" ,"a\n" ] `, scanJSON, []string{"b10", "b9", "a\n"}},
+		{"escaped quotes", `["a \"1\"","é"]`, scanJSON, []string{`a "1"`, "é"}},
+		{"numbers and booleans", `[10,2.5,true]`, scanJSON, []string{"10", "2.5", "true"}},
+		{"lines", "\"b10\"\r\n\n  \"b9\"  \n7", scanJSONLines, []string{"b10", "b9", "7"}},
+		{"empty lines", "", scanJSONLines, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Read all at once and a byte at a time, so values are split
+			// across reads.
+			for _, reader := range []io.Reader{
+				strings.NewReader(tc.input),
+				iotest.OneByteReader(strings.NewReader(tc.input)),
+			} {
+				actual, err := scan(reader, tc.split(newJSONValues()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if expected := tc.expected; !reflect.DeepEqual(expected, actual) {
+					t.Errorf("expected: %q, actual: %q", expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestScanJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, input string
+		split       func(*jsonValues) bufio.SplitFunc
+	}{
+		{"empty", "", scanJSON},
+		{"not an array", `"a"`, scanJSON},
+		{"unterminated", `["a",`, scanJSON},
+		{"unterminated string", `["a`, scanJSON},
+		{"trailing comma", `["a",]`, scanJSON},
+		{"missing comma", `["a" "b"]`, scanJSON},
+		{"after array", `["a"] x`, scanJSON},
+		{"object", `[{"a":1}]`, scanJSON},
+		{"null", `[null]`, scanJSON},
+		{"invalid value", `[nope]`, scanJSON},
+		{"invalid line", "\"a\"\nb", scanJSONLines},
+		{"array line", `["a"]`, scanJSONLines},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := scan(strings.NewReader(tc.input), tc.split(newJSONValues())); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestIOFlagsJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"json", []string{"-input.format=json", "-output.format=json"}, `["a<1>","b \"2\""]`},
+		{"jsonl", []string{"-input.format=json", "-output.format=jsonl"}, "\"a<1>\"\n\"b \\\"2\\\"\""},
+		{"json to separator", []string{"-input.format=json", "-separator=;"}, `a<1>;b "2"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flagset := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags := newIOFlags(flagset)
+			if err := flagset.Parse(append(tc.args, `-input=["a<1>","b \"2\""]`)); err != nil {
+				t.Fatal(err)
+			}
+
+			split, err := flags.validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			items, err := scan(strings.NewReader(*flags.input), split)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected, actual := tc.expected, flags.join(items); expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, format := range []string{formatJSON, formatJSONL} {
+			flagset := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags := newIOFlags(flagset)
+			if err := flagset.Parse([]string{"-input.format=" + format, "-output.format=json", `-input=[10, 9, true, "10", 2.5e1, "a"]`}); err != nil {
+				t.Fatal(err)
+			}
+			if format == formatJSONL {
+				*flags.input = "10\n9\ntrue\n\"10\"\n2.5e1\n\"a\""
+			}
+
+			split, err := flags.validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			items, err := scan(strings.NewReader(*flags.input), split)
+			if err != nil {
+				t.Fatal(err)
+			}
+			natural.NewSorter().Sort(items)
+
+			if expected, actual := `[2.5e1,9,10,"10","a",true]`, flags.join(items); expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		}
+	})
+
+	t.Run("numbers sort as text", func(t *testing.T) {
+		flagset := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := newIOFlags(flagset)
+		if err := flagset.Parse([]string{"-input.format=json", "-output.format=json", `-input=[1000, -1, 1e3, 1, -10, 2.5, 10]`}); err != nil {
+			t.Fatal(err)
+		}
+
+		split, err := flags.validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		items, err := scan(strings.NewReader(*flags.input), split)
+		if err != nil {
+			t.Fatal(err)
+		}
+		natural.NewSorter().Sort(items)
+
+		// Exponents and signs are text, so neither is sorted by value.
+		if expected, actual := `[1e3,1,2.5,10,1000,-1,-10]`, flags.join(items); expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"-input.format=xml"},
+			{"-output.format=xml"},
+			{"-input.format=json", "-lines"},
+			{"-input.format=jsonl", "-extract=[a-z]+"},
+			{"-output.format=json", "-output.quote=double"},
+			{"-output.format=jsonl", "-output.prefix=<"},
+		} {
+			flagset := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags := newIOFlags(flagset)
+			if err := flagset.Parse(append(args, "-input=a")); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := flags.validate(); err == nil {
+				t.Errorf("expected error (args: %q)", args)
+			}
+		}
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		file, err := fsys.Create("input.json.gz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := gzip.NewWriter(file)
+		if _, err := w.Write([]byte(`["a10","a9"]`)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		reader, err := read(fsys, "", "input.json.gz", true, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+
+		values := newJSONValues()
+		out := output{separator: ",", open: "[", close: "]", quote: values.encode}
+		iso := splitJoin{Split: scanJSON(values), Join: out.join}
+		if err := perform(iso, sortWith(natural.NewSorter(), false), reader, write(fsys, "output.json.gz", true, false, "\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := fsys.Open("output.json.gz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		gr, err := gzip.NewReader(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(gr); err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := "[\"a9\",\"a10\"]\n", buf.String(); expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	})
+}
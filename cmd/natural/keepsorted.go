@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SimonRichardson/naturalsort/pkg/fs"
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultKeepSortedMarker = "natural-sort"
+	defaultKeepSortedCheck  = false
+)
+
+// runKeepSorted sorts the lines between markers in files
+func runKeepSorted(args []string) error {
+	// flags for the keep-sorted command
+	var (
+		flagset = flag.NewFlagSet("keep-sorted", flag.ExitOnError)
+
+		marker = flagset.String("marker", defaultKeepSortedMarker, "marker that starts (`<marker> start`) and ends (`<marker> end`) a block")
+		check  = flagset.Bool("check", defaultKeepSortedCheck, "check the blocks are sorted, rather than sorting them")
+	)
+	flagset.Usage = usageFor(flagset, "keep-sorted [flags] <file>...")
+	if err := flagset.Parse(args); err != nil {
+		return err
+	}
+
+	if flagset.NArg() == 0 {
+		return errorFor(flagset, "keep-sorted [flags] <file>...", errors.New("no files"))
+	}
+	if strings.TrimSpace(*marker) == "" {
+		return errorFor(flagset, "keep-sorted [flags] <file>...", errors.Errorf("invalid marker (marker: %q)", *marker))
+	}
+
+	var (
+		fsys     = fs.NewRealFilesystem()
+		unsorted int
+	)
+	for _, path := range flagset.Args() {
+		lines, err := keepSortedFile(fsys, path, *marker, *check)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			if *check {
+				fmt.Fprintf(os.Stderr, "%s:%d: block is not sorted\n", path, line)
+			} else {
+				fmt.Fprintf(os.Stderr, "%s:%d: sorted block\n", path, line)
+			}
+		}
+		unsorted += len(lines)
+	}
+	if *check && unsorted > 0 {
+		return errors.Errorf("not sorted (blocks: %d)", unsorted)
+	}
+	return nil
+}
+
+// keepSortedFile sorts the blocks in the file, returning the lines of the
+// blocks that were not sorted. With check, the file is left as it is.
+func keepSortedFile(fsys fs.Filesystem, path, marker string, check bool) ([]int, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	sorted, lines, err := keepSorted(content, marker, natural.NewSorter())
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", path)
+	}
+	if check || len(lines) == 0 {
+		return lines, nil
+	}
+
+	if file, err = fsys.Create(path); err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	_, err = file.Write(sorted)
+	return lines, err
+}
+
+// keepSorted sorts the lines of every block between `<marker> start` and
+// `<marker> end`, returning the sorted content along with the lines of the
+// blocks that changed. Blank lines split a block into groups that are sorted
+// on their own.
+func keepSorted(content []byte, marker string, sorter *natural.Sorter) ([]byte, []int, error) {
+	var (
+		lines    = strings.SplitAfter(string(content), "\n")
+		pattern  = markerPattern(marker)
+		changed  []int
+		blockPos = -1
+	)
+	for i := 0; i < len(lines); i++ {
+		var kind string
+		if match := pattern.FindStringSubmatch(lines[i]); match != nil {
+			kind = match[1]
+		}
+
+		switch kind {
+		case "start":
+			if blockPos != -1 {
+				return nil, nil, errors.Errorf("nested block (line: %d)", i+1)
+			}
+			blockPos = i + 1
+
+		case "end":
+			if blockPos == -1 {
+				return nil, nil, errors.Errorf("unexpected end of block (line: %d)", i+1)
+			}
+
+			if sortBlock(lines[blockPos:i], sorter) {
+				changed = append(changed, blockPos)
+			}
+			blockPos = -1
+		}
+	}
+	if blockPos != -1 {
+		return nil, nil, errors.Errorf("unterminated block (line: %d)", blockPos)
+	}
+	return []byte(strings.Join(lines, "")), changed, nil
+}
+
+// commentStart matches the start of a comment, in the languages that blocks
+// are commonly found in.
+const commentStart = `^\s*(?:#|//|/\*|<!--|--|;|%)`
+
+// commentPattern matches a line that is a comment, where the comment start
+// is followed by a space so that lines such as `--flag` aren't comments.
+var commentPattern = regexp.MustCompile(commentStart + `(?:\s|$)`)
+
+// markerPattern matches a line that is a comment starting with the marker,
+// such as `# natural-sort start` or `<!-- natural-sort end -->`, so the
+// marker can be mentioned elsewhere without starting a block.
+func markerPattern(marker string) *regexp.Regexp {
+	return regexp.MustCompile(commentStart + `\s*` + regexp.QuoteMeta(marker) + `\s+(start|end)\b`)
+}
+
+// sortBlock sorts the lines in place, returning if they changed.
+func sortBlock(lines []string, sorter *natural.Sorter) bool {
+	var changed bool
+	for len(lines) > 0 {
+		n := 0
+		for n < len(lines) && strings.TrimSpace(lines[n]) != "" {
+			n++
+		}
+		if sortGroup(lines[:n], sorter) {
+			changed = true
+		}
+
+		// Skip over any blank lines.
+		for n < len(lines) && strings.TrimSpace(lines[n]) == "" {
+			n++
+		}
+		lines = lines[n:]
+	}
+	return changed
+}
+
+// sortGroup sorts the lines in place by their content, ignoring indentation
+// and trailing commas. Lines keep their own indentation, where as trailing
+// commas stay where they were, so a list where every line but the last has
+// a comma still does once sorted. Comment lines move with the line after
+// them, and any comments after the last line stay at the end.
+func sortGroup(lines []string, sorter *natural.Sorter) bool {
+	var (
+		items    []blockLine
+		commas   []bool
+		comments []string
+	)
+	for _, line := range lines {
+		if commentPattern.MatchString(line) {
+			comments = append(comments, line)
+			continue
+		}
+		item := parseBlockLine(line)
+		item.comments, comments = comments, nil
+		items = append(items, item)
+		commas = append(commas, item.comma)
+	}
+
+	sort.SliceStable(items, func(a, b int) bool {
+		return sorter.Less(items[a].key, items[b].key)
+	})
+
+	sorted := make([]string, 0, len(lines))
+	for i, item := range items {
+		item.comma = commas[i]
+		sorted = append(sorted, item.comments...)
+		sorted = append(sorted, item.String())
+	}
+	sorted = append(sorted, comments...)
+
+	var changed bool
+	for i, line := range sorted {
+		if line != lines[i] {
+			lines[i] = line
+			changed = true
+		}
+	}
+	return changed
+}
+
+// blockLine is a line in a block, split up so that it can be sorted by its
+// content and any trailing comma moved.
+type blockLine struct {
+	// body is the line without the trailing comma or line ending.
+	body string
+	// key is the content of the line that's compared.
+	key   string
+	comma bool
+	// tail is any whitespace and line ending after the body and comma.
+	tail string
+	// comments are the comment lines before the line.
+	comments []string
+}
+
+func parseBlockLine(line string) blockLine {
+	body := strings.TrimRight(line, " \t\r\n")
+	tail := line[len(body):]
+
+	comma := strings.HasSuffix(body, ",")
+	if comma {
+		body = body[:len(body)-1]
+	}
+
+	return blockLine{
+		body:  body,
+		key:   strings.TrimSpace(body),
+		comma: comma,
+		tail:  tail,
+	}
+}
+
+func (l blockLine) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(l.body)
+	if l.comma {
+		buf.WriteByte(',')
+	}
+	buf.WriteString(l.tail)
+	return buf.String()
+}
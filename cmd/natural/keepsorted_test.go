@@ -0,0 +1,246 @@
+package main
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/SimonRichardson/naturalsort/pkg/fs"
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+func TestKeepSorted(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		changed  []int
+	}{
+		{
+			name:     "no blocks",
+			input:    "b\na\n",
+			expected: "b\na\n",
+		},
+		{
+			name: "yaml",
+			input: "hosts:\n" +
+				"  # natural-sort start\n" +
+				"  - web10\n" +
+				"  - web2\n" +
+				"  - web1\n" +
+				"  # natural-sort end\n",
+			expected: "hosts:\n" +
+				"  # natural-sort start\n" +
+				"  - web1\n" +
+				"  - web2\n" +
+				"  - web10\n" +
+				"  # natural-sort end\n",
+			changed: []int{2},
+		},
+		{
+			name: "trailing commas",
+			input: "[\n" +
+				"\t// natural-sort start\n" +
+				"\t\"v10\",\n" +
+				"\t\"v9\",\n" +
+				"\t\"v1\"\n" +
+				"\t// natural-sort end\n" +
+				"]\n",
+			expected: "[\n" +
+				"\t// natural-sort start\n" +
+				"\t\"v1\",\n" +
+				"\t\"v9\",\n" +
+				"\t\"v10\"\n" +
+				"\t// natural-sort end\n" +
+				"]\n",
+			changed: []int{2},
+		},
+		{
+			name: "comments",
+			input: "# natural-sort start\n" +
+				"# the last host\n" +
+				"- web10\n" +
+				"# the first hosts\n" +
+				"#\n" +
+				"- web2\n" +
+				"- web1\n" +
+				"# end of the hosts\n" +
+				"# natural-sort end\n",
+			expected: "# natural-sort start\n" +
+				"- web1\n" +
+				"# the first hosts\n" +
+				"#\n" +
+				"- web2\n" +
+				"# the last host\n" +
+				"- web10\n" +
+				"# end of the hosts\n" +
+				"# natural-sort end\n",
+			changed: []int{1},
+		},
+		{
+			name: "comments and trailing commas",
+			input: "[\n" +
+				"\t// natural-sort start\n" +
+				"\t\"--v10\",\n" +
+				"\t// v1 comes first\n" +
+				"\t\"--v1\",\n" +
+				"\t\"--v9\"\n" +
+				"\t// natural-sort end\n" +
+				"]\n",
+			expected: "[\n" +
+				"\t// natural-sort start\n" +
+				"\t// v1 comes first\n" +
+				"\t\"--v1\",\n" +
+				"\t\"--v9\",\n" +
+				"\t\"--v10\"\n" +
+				"\t// natural-sort end\n" +
+				"]\n",
+			changed: []int{2},
+		},
+		{
+			name: "lines that look like comments",
+			input: "# natural-sort start\n" +
+				"--verbose\n" +
+				"--debug\n" +
+				"# natural-sort end\n",
+			expected: "# natural-sort start\n" +
+				"--debug\n" +
+				"--verbose\n" +
+				"# natural-sort end\n",
+			changed: []int{1},
+		},
+		{
+			name: "blank lines",
+			input: "<!-- natural-sort start -->\n" +
+				"- b2\n" +
+				"- b1\n" +
+				"\n" +
+				"- a2\n" +
+				"- a1\n" +
+				"<!-- natural-sort end -->\n",
+			expected: "<!-- natural-sort start -->\n" +
+				"- b1\n" +
+				"- b2\n" +
+				"\n" +
+				"- a1\n" +
+				"- a2\n" +
+				"<!-- natural-sort end -->\n",
+			changed: []int{1},
+		},
+		{
+			name: "marker mentioned in code",
+			input: "fmt.Println(\"# natural-sort start\")\n" +
+				"b\n" +
+				"a\n" +
+				"x := \"natural-sort end\" // natural-sort end\n",
+			expected: "fmt.Println(\"# natural-sort start\")\n" +
+				"b\n" +
+				"a\n" +
+				"x := \"natural-sort end\" // natural-sort end\n",
+		},
+		{
+			name: "several blocks",
+			input: "# natural-sort start\n" +
+				"a1\n" +
+				"a2\n" +
+				"# natural-sort end\n" +
+				"z\n" +
+				"y\n" +
+				"# natural-sort start\n" +
+				"c10\n" +
+				"c9\n" +
+				"# natural-sort end",
+			expected: "# natural-sort start\n" +
+				"a1\n" +
+				"a2\n" +
+				"# natural-sort end\n" +
+				"z\n" +
+				"y\n" +
+				"# natural-sort start\n" +
+				"c9\n" +
+				"c10\n" +
+				"# natural-sort end",
+			changed: []int{7},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, changed, err := keepSorted([]byte(tc.input), "natural-sort", natural.NewSorter())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected, actual := tc.expected, string(actual); expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+			if expected, actual := tc.changed, changed; !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected: %v, actual: %v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestKeepSortedErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{
+		"# natural-sort start\na\n",
+		"a\n# natural-sort end\n",
+		"# natural-sort start\n# natural-sort start\n# natural-sort end\n",
+	} {
+		if _, _, err := keepSorted([]byte(input), "natural-sort", natural.NewSorter()); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestKeepSortedFile(t *testing.T) {
+	t.Parallel()
+
+	const (
+		input    = "# natural-sort start\nb\na\n# natural-sort end\n"
+		expected = "# natural-sort start\na\nb\n# natural-sort end\n"
+	)
+
+	fsys := fs.NewVirtualFilesystem()
+	file, err := fsys.Create("list.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write([]byte(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	readFile := func() string {
+		file, err := fsys.Open("list.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	// Checking leaves the file alone.
+	lines, err := keepSortedFile(fsys, "list.txt", "natural-sort", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := []int{1}, lines; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if actual := readFile(); input != actual {
+		t.Errorf("expected: %q, actual: %q", input, actual)
+	}
+
+	if _, err := keepSortedFile(fsys, "list.txt", "natural-sort", false); err != nil {
+		t.Fatal(err)
+	}
+	if actual := readFile(); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
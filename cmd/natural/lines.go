@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+)
+
+const (
+	blankKeep = "keep"
+	blankDrop = "drop"
+)
+
+// bom is the UTF-8 byte order mark, which some editors put at the start of
+// files.
+var bom = []byte{0xef, 0xbb, 0xbf}
+
+// scanLines splits the input into lines, ending in `\n`, `\r\n` or `\r`. Any
+// byte order mark at the start of the input is removed, along with blank lines
+// when the policy is to drop them. The first line ending found is recorded in
+// ending, so the output can use the same style.
+func scanLines(blank string, ending *string) bufio.SplitFunc {
+	start := true
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if start {
+			if !atEOF && len(data) < len(bom) && bytes.HasPrefix(bom, data) {
+				return 0, nil, nil
+			}
+			start = false
+			if bytes.HasPrefix(data, bom) {
+				advance = len(bom)
+			}
+		}
+
+		// Skip over lines rather than returning without a token, as the
+		// scanner stops at the end of the input when there's no token.
+		for {
+			line := data[advance:]
+			i := bytes.IndexAny(line, "\r\n")
+			if i == -1 {
+				if atEOF && len(line) > 0 && !isDropped(line, blank) {
+					return len(data), line, nil
+				}
+				if atEOF {
+					return len(data), nil, nil
+				}
+				return advance, nil, nil
+			}
+
+			n := 1
+			if line[i] == '\r' {
+				if i+1 == len(line) && !atEOF {
+					// Wait to see if it's `\r\n`.
+					return advance, nil, nil
+				}
+				if i+1 < len(line) && line[i+1] == '\n' {
+					n = 2
+				}
+			}
+			if *ending == "" {
+				*ending = string(line[i : i+n])
+			}
+
+			advance += i + n
+			if !isDropped(line[:i], blank) {
+				return advance, line[:i], nil
+			}
+		}
+	}
+}
+
+// isDropped returns if the line is blank and blank lines are dropped.
+func isDropped(line []byte, blank string) bool {
+	return blank == blankDrop && len(bytes.TrimSpace(line)) == 0
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanLines(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, input, blank string
+		expected           []string
+		ending             string
+	}{
+		{"empty", "", blankKeep, nil, ""},
+		{"unix", "b\na\n", blankKeep, []string{"b", "a"}, "\n"},
+		{"windows", "b\r\na\r\n", blankKeep, []string{"b", "a"}, "\r\n"},
+		{"classic mac", "b\ra", blankKeep, []string{"b", "a"}, "\r"},
+		{"no ending", "b", blankKeep, []string{"b"}, ""},
+		{"bom", "\xef\xbb\xbfb\r\na", blankKeep, []string{"b", "a"}, "\r\n"},
+		{"only bom", "\xef\xbb\xbf", blankKeep, nil, ""},
+		{"keep blank", "b\n\n \na\n", blankKeep, []string{"b", "", " ", "a"}, "\n"},
+		{"drop blank", "b\n\n \na\n", blankDrop, []string{"b", "a"}, "\n"},
+		{"drop blank at the end", "b\n\n", blankDrop, []string{"b"}, "\n"},
+		{"bom and drop blank", "\xef\xbb\xbf\r\nb\r\n\r\na\r\n", blankDrop, []string{"b", "a"}, "\r\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Read all at once and a byte at a time, so line endings and the
+			// byte order mark are split across reads.
+			for _, reader := range []io.Reader{
+				strings.NewReader(tc.input),
+				iotest.OneByteReader(strings.NewReader(tc.input)),
+			} {
+				var ending string
+				actual, err := scan(reader, scanLines(tc.blank, &ending))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if expected := tc.expected; !reflect.DeepEqual(expected, actual) {
+					t.Errorf("expected: %q, actual: %q", expected, actual)
+				}
+				if expected, actual := tc.ending, ending; expected != actual {
+					t.Errorf("expected: %q, actual: %q", expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestIOFlagsLines(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                  string
+		args                  []string
+		separator, terminator string
+	}{
+		{"default", []string{"-lines"}, "\n", "\n"},
+		{"keep ending", []string{"-lines", "-lines.keepending"}, "\r\n", "\r\n"},
+		{"output separator", []string{"-lines", "-lines.keepending", "-output.separator=;"}, ";", "\r\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flagset := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags := newIOFlags(flagset)
+			if err := flagset.Parse(append(tc.args, "-input=b\r\na\r\n")); err != nil {
+				t.Fatal(err)
+			}
+
+			split, err := flags.validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := scan(strings.NewReader(*flags.input), split); err != nil {
+				t.Fatal(err)
+			}
+
+			out, err := flags.output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected, actual := tc.separator, out.separator; expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+			if expected, actual := tc.terminator, out.terminator; expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/SimonRichardson/naturalsort/pkg/fs"
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultLsRecursive = false
+	defaultLsDirsFirst = false
+	defaultLsLong      = false
+
+	lsTimeFormat = "2006-01-02 15:04"
+)
+
+// runLs lists the entries of a directory in natural order
+func runLs(args []string) error {
+	// flags for the ls command
+	var (
+		flagset = flag.NewFlagSet("ls", flag.ExitOnError)
+
+		recursive = flagset.Bool("recursive", defaultLsRecursive, "list the entries of every directory below dir")
+		dirsFirst = flagset.Bool("dirsfirst", defaultLsDirsFirst, "place directories before files")
+		long      = flagset.Bool("long", defaultLsLong, "include the mode, size and modification time")
+		match     = flagset.String("match", "", "comma separated glob patterns, only list names that match")
+		exclude   = flagset.String("exclude", "", "comma separated glob patterns, skip names that match")
+	)
+	flagset.Usage = usageFor(flagset, "ls [flags] [dir]")
+	if err := flagset.Parse(args); err != nil {
+		return err
+	}
+
+	dir := "."
+	if flagset.NArg() > 0 {
+		dir = flagset.Arg(0)
+	}
+
+	opts := lsOptions{
+		recursive: *recursive,
+		dirsFirst: *dirsFirst,
+		long:      *long,
+		match:     splitList(*match),
+		exclude:   splitList(*exclude),
+	}
+	if err := opts.validate(); err != nil {
+		return errorFor(flagset, "ls [flags] [dir]", err)
+	}
+
+	return listDir(fs.NewRealFilesystem(), dir, opts, os.Stdout)
+}
+
+// lsOptions changes which entries are listed and how.
+type lsOptions struct {
+	recursive bool
+	dirsFirst bool
+	long      bool
+
+	// match only lists entries with names that match one of the patterns,
+	// directories that don't match are still listed when recursive.
+	match []string
+	// exclude skips entries with names that match any of the patterns,
+	// including the contents of directories.
+	exclude []string
+}
+
+func (o lsOptions) validate() error {
+	for _, pattern := range append(o.match, o.exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return errors.Errorf("invalid pattern (pattern: %q)", pattern)
+		}
+	}
+	return nil
+}
+
+// listDir writes the entries of dir to w in natural order. Entries found when
+// recursive are written relative to dir, straight after their directory.
+func listDir(fsys fs.Filesystem, dir string, opts lsOptions, w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	if err := opts.list(fsys, dir, "", writer); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func (o lsOptions) list(fsys fs.Filesystem, dir, rel string, w io.Writer) error {
+	infos, err := fsys.ReadDir(filepath.Join(dir, rel))
+	if err != nil {
+		return err
+	}
+
+	var entries []os.FileInfo
+	for _, info := range infos {
+		if !matchAny(o.exclude, info.Name()) {
+			entries = append(entries, info)
+		}
+	}
+	sortEntries(entries, o.dirsFirst)
+
+	for _, info := range entries {
+		path := filepath.Join(rel, info.Name())
+		if len(o.match) == 0 || matchAny(o.match, info.Name()) {
+			if err := o.print(w, path, info); err != nil {
+				return err
+			}
+		}
+		if o.recursive && info.IsDir() {
+			if err := o.list(fsys, dir, path, w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (o lsOptions) print(w io.Writer, path string, info os.FileInfo) error {
+	if !o.long {
+		_, err := fmt.Fprintln(w, path)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", info.Mode(), info.Size(), info.ModTime().Format(lsTimeFormat), path)
+	return err
+}
+
+// sortEntries sorts the entries naturally by name, optionally placing
+// directories before files.
+func sortEntries(entries []os.FileInfo, dirsFirst bool) {
+	sorter := natural.NewSorter()
+	sort.SliceStable(entries, func(a, b int) bool {
+		x, y := entries[a], entries[b]
+		if dirsFirst && x.IsDir() != y.IsDir() {
+			return x.IsDir()
+		}
+		return sorter.Less(x.Name(), y.Name())
+	})
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
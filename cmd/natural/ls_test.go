@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/SimonRichardson/naturalsort/pkg/fs"
+)
+
+func TestListDir(t *testing.T) {
+	t.Parallel()
+
+	fsys := fs.NewVirtualFilesystem()
+	createFiles(t, fsys,
+		"root/file10.txt",
+		"root/file2.txt",
+		"root/notes.md",
+		"root/dir10/a.txt",
+		"root/dir2/b1.txt",
+		"root/dir2/b10.md",
+		"root/dir2/b9.txt",
+	)
+
+	testCases := []struct {
+		name     string
+		opts     lsOptions
+		expected string
+	}{
+		{
+			name:     "default",
+			opts:     lsOptions{},
+			expected: "dir2\ndir10\nfile2.txt\nfile10.txt\nnotes.md\n",
+		},
+		{
+			name:     "recursive",
+			opts:     lsOptions{recursive: true},
+			expected: "dir2\ndir2/b1.txt\ndir2/b9.txt\ndir2/b10.md\ndir10\ndir10/a.txt\nfile2.txt\nfile10.txt\nnotes.md\n",
+		},
+		{
+			name:     "match",
+			opts:     lsOptions{recursive: true, match: []string{"*.txt"}},
+			expected: "dir2/b1.txt\ndir2/b9.txt\ndir10/a.txt\nfile2.txt\nfile10.txt\n",
+		},
+		{
+			name:     "exclude",
+			opts:     lsOptions{recursive: true, exclude: []string{"dir2", "*.md"}},
+			expected: "dir10\ndir10/a.txt\nfile2.txt\nfile10.txt\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := listDir(fsys, "root", tc.opts, &buf); err != nil {
+				t.Fatal(err)
+			}
+			if expected, actual := tc.expected, buf.String(); expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
+
+func TestListDirDirsFirst(t *testing.T) {
+	t.Parallel()
+
+	fsys := fs.NewVirtualFilesystem()
+	createFiles(t, fsys, "root/a1", "root/b2/x", "root/c3")
+
+	var buf bytes.Buffer
+	if err := listDir(fsys, "root", lsOptions{dirsFirst: true}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := "b2\na1\nc3\n", buf.String(); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
+
+func TestListDirLong(t *testing.T) {
+	t.Parallel()
+
+	fsys := fs.NewVirtualFilesystem()
+	createFiles(t, fsys, "root/a10", "root/a9")
+
+	infos, err := fsys.ReadDir("root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modTimes := map[string]string{}
+	for _, info := range infos {
+		modTimes[info.Name()] = info.ModTime().Format(lsTimeFormat)
+	}
+
+	var buf bytes.Buffer
+	if err := listDir(fsys, "root", lsOptions{long: true}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each file contains its own path.
+	expected := fmt.Sprintf("-rw-r--r--  7  %s  a9\n-rw-r--r--  8  %s  a10\n", modTimes["a9"], modTimes["a10"])
+	if actual := buf.String(); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
+
+func TestLsOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	if err := (lsOptions{match: []string{"[a-"}}).validate(); err == nil {
+		t.Error("expected error")
+	}
+	if err := (lsOptions{match: []string{"*.go"}, exclude: []string{"vendor"}}).validate(); err != nil {
+		t.Error(err)
+	}
+}
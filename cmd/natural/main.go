@@ -32,6 +32,20 @@ func main() {
 	switch strings.ToLower(os.Args[1]) {
 	case "sort":
 		cmd = runSort
+	case "gaps":
+		cmd = runGaps
+	case "compact":
+		cmd = runCompact
+	case "pad":
+		cmd = runPad
+	case "rename":
+		cmd = runRename
+	case "ls":
+		cmd = runLs
+	case "compare":
+		cmd = runCompare
+	case "keep-sorted":
+		cmd = runKeepSorted
 	default:
 		usage()
 	}
@@ -44,7 +58,14 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "  %s <mode> [flags]\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "MODES\n")
-	fmt.Fprintf(os.Stderr, "  sort       Perform a natural sort \n")
+	fmt.Fprintf(os.Stderr, "  sort         Perform a natural sort \n")
+	fmt.Fprintf(os.Stderr, "  gaps         Report missing numbers in sequences\n")
+	fmt.Fprintf(os.Stderr, "  compact      Shorten sequences into range notation\n")
+	fmt.Fprintf(os.Stderr, "  pad          Zero-pad numbers so lexical order is natural\n")
+	fmt.Fprintf(os.Stderr, "  rename       Rename files into natural order safe names\n")
+	fmt.Fprintf(os.Stderr, "  ls           List directory entries in natural order\n")
+	fmt.Fprintf(os.Stderr, "  compare      Compare two strings and explain the result\n")
+	fmt.Fprintf(os.Stderr, "  keep-sorted  Sort the lines between markers in files\n")
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "VERSION\n")
 	fmt.Fprintf(os.Stderr, "  %s (%s)\n", version, runtime.Version())
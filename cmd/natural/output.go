@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const (
+	quoteNone   = "none"
+	quoteSingle = "single"
+	quoteDouble = "double"
+)
+
+// quoters quote a value for each of the output.quote modes.
+var quoters = map[string]func(string) string{
+	quoteNone: func(s string) string {
+		return s
+	},
+	// Single quotes are quoted the way a shell expects, so the output can be
+	// pasted into a command.
+	quoteSingle: func(s string) string {
+		return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+	},
+	quoteDouble: strconv.Quote,
+}
+
+// output is how values are written out.
+type output struct {
+	separator  string
+	terminator string
+	prefix     string
+	suffix     string
+	quote      func(string) string
+	// open and close are written around all the values, such as for a JSON
+	// array.
+	open, close string
+}
+
+// join quotes each value, wrapping it in the prefix and suffix, and joins them
+// with the separator. The terminator is written separately.
+func (o output) join(x []string) string {
+	var buf bytes.Buffer
+	buf.WriteString(o.open)
+	for i, v := range x {
+		if i > 0 {
+			buf.WriteString(o.separator)
+		}
+		buf.WriteString(o.prefix)
+		buf.WriteString(o.quote(v))
+		buf.WriteString(o.suffix)
+	}
+	buf.WriteString(o.close)
+	return buf.String()
+}
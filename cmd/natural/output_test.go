@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestOutputJoin(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		output   output
+		expected string
+	}{
+		{
+			name:     "separator",
+			output:   output{separator: "\n", quote: quoters[quoteNone]},
+			expected: "a1\nit's\na 2",
+		},
+		{
+			name:     "prefix and suffix",
+			output:   output{separator: ", ", prefix: "<", suffix: ">", quote: quoters[quoteNone]},
+			expected: "<a1>, <it's>, <a 2>",
+		},
+		{
+			name:     "single",
+			output:   output{separator: " ", quote: quoters[quoteSingle]},
+			expected: `'a1' 'it'\''s' 'a 2'`,
+		},
+		{
+			name:     "double",
+			output:   output{separator: ",", prefix: "[", suffix: "]", quote: quoters[quoteDouble]},
+			expected: `["a1"],["it's"],["a 2"]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if expected, actual := tc.expected, tc.output.join([]string{"a1", "it's", "a 2"}); expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
+
+func TestIOFlagsOutput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		flagset := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := newIOFlags(flagset)
+		if err := flagset.Parse([]string{"-separator=;"}); err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := flags.output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := ";", out.separator; expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+		if expected, actual := "\n", out.terminator; expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	})
+
+	t.Run("escapes", func(t *testing.T) {
+		flagset := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := newIOFlags(flagset)
+		if err := flagset.Parse([]string{`-output.separator=\0`, `-output.terminator=\0`}); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, actual := "a\x00b", flags.join([]string{"a", "b"}); expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	})
+
+	t.Run("invalid quote", func(t *testing.T) {
+		flagset := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := newIOFlags(flagset)
+		if err := flagset.Parse([]string{"-output.quote=backtick"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := flags.output(); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
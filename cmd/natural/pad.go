@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+const (
+	defaultPadSort = false
+)
+
+// runPad zero-pads the input, so that lexical order matches natural order
+func runPad(args []string) error {
+	// flags for the pad command
+	var (
+		flagset = flag.NewFlagSet("pad", flag.ExitOnError)
+		flags   = newIOFlags(flagset)
+
+		sort = flagset.Bool("sort", defaultPadSort, "natural sort the input after padding")
+	)
+	flagset.Usage = usageFor(flagset, "pad [flags]")
+	if err := flagset.Parse(args); err != nil {
+		return err
+	}
+
+	splitFn, err := flags.validate()
+	if err != nil {
+		return errorFor(flagset, "pad [flags]", err)
+	}
+
+	return flags.run(func(reader io.Reader, writer writeFn) error {
+		items, err := scan(reader, splitFn)
+		if err != nil {
+			return err
+		}
+
+		items, err = natural.Normalize(items)
+		if err != nil {
+			return err
+		}
+		if *sort {
+			natural.Sort(items)
+		}
+
+		out := flags.join(items)
+		return writer(bytes.NewBufferString(out))
+	})
+}
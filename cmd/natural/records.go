@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+	"github.com/pkg/errors"
+)
+
+const (
+	formatCSV = "csv"
+	formatTSV = "tsv"
+)
+
+// keyPattern matches keys in the style of POSIX sort, `2`, `2,2` or `1n`,
+// which are the first and last fields of the key, each followed by any
+// modifiers.
+var keyPattern = regexp.MustCompile(`^(\d+)([a-zA-Z]*)(?:,(\d+)([a-zA-Z]*))?$`)
+
+// key is the fields of a record to compare and how to compare them. Fields
+// are counted from 1, an end of 0 is the last field.
+type key struct {
+	start, end int
+	reverse    bool // r
+	ignoreCase bool // f
+	numeric    bool // n
+	semver     bool // V
+}
+
+func parseKey(value string) (key, error) {
+	match := keyPattern.FindStringSubmatch(value)
+	if match == nil {
+		return key{}, errors.Errorf("invalid key (key: %q)", value)
+	}
+
+	var k key
+	k.start, _ = strconv.Atoi(match[1])
+	if match[3] != "" {
+		k.end, _ = strconv.Atoi(match[3])
+	}
+	if k.start < 1 || (match[3] != "" && k.end < k.start) {
+		return key{}, errors.Errorf("invalid key fields (key: %q)", value)
+	}
+
+	for _, m := range match[2] + match[4] {
+		switch m {
+		case 'r':
+			k.reverse = true
+		case 'f':
+			k.ignoreCase = true
+		case 'n':
+			k.numeric = true
+		case 'V':
+			k.semver = true
+		default:
+			return key{}, errors.Errorf("invalid key modifier (key: %q, modifier: %q)", value, m)
+		}
+	}
+	if k.numeric && k.semver {
+		return key{}, errors.Errorf("numeric and semver can't both be used (key: %q)", value)
+	}
+	return k, nil
+}
+
+func (k key) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(strconv.Itoa(k.start))
+	if k.end != 0 {
+		fmt.Fprintf(&buf, ",%d", k.end)
+	}
+	for _, m := range []struct {
+		ok   bool
+		flag byte
+	}{
+		{k.reverse, 'r'},
+		{k.ignoreCase, 'f'},
+		{k.numeric, 'n'},
+		{k.semver, 'V'},
+	} {
+		if m.ok {
+			buf.WriteByte(m.flag)
+		}
+	}
+	return buf.String()
+}
+
+// keyList is a flag of the keys to sort records by, it can be given more than
+// once, with earlier keys taking priority.
+type keyList []key
+
+func (k *keyList) String() string {
+	res := make([]string, len(*k))
+	for i, key := range *k {
+		res[i] = key.String()
+	}
+	return strings.Join(res, " ")
+}
+
+func (k *keyList) Set(value string) error {
+	key, err := parseKey(value)
+	if err != nil {
+		return err
+	}
+	*k = append(*k, key)
+	return nil
+}
+
+// recordSorter sorts records by their fields.
+type recordSorter struct {
+	keys    []key
+	sorter  *natural.Sorter
+	compare []func(a, b string) int
+	unique  bool
+}
+
+// newRecordSorter yields a recordSorter that compares the fields of each key
+// using a Sorter with the options, along with the modifiers of the key.
+// Without any keys every field is compared in order.
+func newRecordSorter(keys []key, options []natural.Option, unique bool) recordSorter {
+	r := recordSorter{
+		keys:    keys,
+		sorter:  natural.NewSorter(options...),
+		compare: make([]func(a, b string) int, len(keys)),
+		unique:  unique,
+	}
+	for i, k := range keys {
+		if k.numeric {
+			r.compare[i] = compareNumeric
+			continue
+		}
+
+		opts := append([]natural.Option(nil), options...)
+		if k.ignoreCase {
+			opts = append(opts, natural.WithCaseFolding())
+		}
+		if k.semver {
+			opts = append(opts, natural.WithSemver())
+		}
+		r.compare[i] = natural.NewSorter(opts...).Compare
+	}
+	return r
+}
+
+// Sort sorts the records, keeping records that compare the same in their
+// original order. With unique, only the first of the records that compare
+// the same is kept.
+func (r recordSorter) Sort(records [][]string) [][]string {
+	sort.SliceStable(records, func(a, b int) bool {
+		return r.Compare(records[a], records[b]) < 0
+	})
+	if !r.unique {
+		return records
+	}
+
+	var res [][]string
+	for i, record := range records {
+		if i > 0 && r.Compare(res[len(res)-1], record) == 0 {
+			continue
+		}
+		res = append(res, record)
+	}
+	return res
+}
+
+// Compare compares the records a key at a time, until a key is different.
+func (r recordSorter) Compare(a, b []string) int {
+	if len(r.keys) == 0 {
+		return compareFields(a, b, 0, 0, r.sorter.Compare)
+	}
+
+	for i, k := range r.keys {
+		c := compareFields(a, b, k.start-1, k.end, r.compare[i])
+		if k.reverse {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareFields compares the fields from start up to end, an end of 0 is the
+// last field.
+func compareFields(a, b []string, start, end int, compare func(a, b string) int) int {
+	if end == 0 {
+		end = len(a)
+		if len(b) > end {
+			end = len(b)
+		}
+	}
+	for i := start; i < end; i++ {
+		if c := compare(column(a, i), column(b, i)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// numberPattern matches the number at the start of a field, the same as
+// `sort -n`.
+var numberPattern = regexp.MustCompile(`^\s*[-+]?(?:\d+(?:\.\d*)?|\.\d+)`)
+
+// compareNumeric compares the numbers at the start of each field, fields that
+// don't start with a number are treated as 0.
+func compareNumeric(a, b string) int {
+	x, y := parseNumber(a), parseNumber(b)
+	if x < y {
+		return -1
+	} else if x > y {
+		return 1
+	}
+	return 0
+}
+
+func parseNumber(s string) float64 {
+	n, err := strconv.ParseFloat(strings.TrimSpace(numberPattern.FindString(s)), 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// sortByKeys sorts values by splitting them into fields, using the separator
+// or runs of whitespace when it's empty.
+func sortByKeys(r recordSorter, separator string) sortFn {
+	return func(x []string) []string {
+		records := make([][]string, len(x))
+		for i, v := range x {
+			records[i] = fields(v, separator)
+		}
+
+		// Sort the indexes, so the values can be put back as they were.
+		indexes := make([]int, len(x))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return r.Compare(records[indexes[a]], records[indexes[b]]) < 0
+		})
+
+		res := make([]string, 0, len(x))
+		for i, index := range indexes {
+			if r.unique && i > 0 && r.Compare(records[indexes[i-1]], records[index]) == 0 {
+				continue
+			}
+			res = append(res, x[index])
+		}
+		return res
+	}
+}
+
+func fields(s, separator string) []string {
+	if separator == "" {
+		return strings.Fields(s)
+	}
+	return strings.Split(s, separator)
+}
+
+// column returns the column of the record, records that are too short have
+// an empty column.
+func column(record []string, i int) string {
+	if i < len(record) {
+		return record[i]
+	}
+	return ""
+}
+
+// performRecords reads CSV records, using comma to separate the fields, and
+// writes them sorted. With header the first record is kept first.
+func performRecords(comma rune, header bool, sorter recordSorter, reader io.Reader, writer writeFn) error {
+	r := csv.NewReader(reader)
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	var head [][]string
+	if header && len(records) > 0 {
+		head, records = records[:1], records[1:]
+	}
+	records = append(head, sorter.Sort(records)...)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = comma
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+
+	// The writer terminates the output, so drop the last new line.
+	buf.Truncate(len(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'})))
+	return writer(&buf)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPerformRecords(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		comma    rune
+		header   bool
+		keys     []string
+		unique   bool
+		input    string
+		expected string
+	}{
+		{
+			name:     "all columns",
+			comma:    ',',
+			input:    "b,1\na,10\na,9\n",
+			expected: "a,9\na,10\nb,1",
+		},
+		{
+			name:     "key",
+			comma:    ',',
+			header:   true,
+			keys:     []string{"3"},
+			input:    "name,host,rack\nweb,web10,r2\ndb,db1,r10\ncache,c1,r2\n",
+			expected: "name,host,rack\nweb,web10,r2\ncache,c1,r2\ndb,db1,r10",
+		},
+		{
+			name:     "keys",
+			comma:    ',',
+			keys:     []string{"2", "1"},
+			input:    "b10,x\na,y\nb9,x\n",
+			expected: "b9,x\nb10,x\na,y",
+		},
+		{
+			name:     "quoted fields",
+			comma:    ',',
+			keys:     []string{"1"},
+			input:    "\"item 10, large\",1\n\"item 9\nsmall\",2\n",
+			expected: "\"item 9\nsmall\",2\n\"item 10, large\",1",
+		},
+		{
+			name:     "tsv",
+			comma:    '\t',
+			keys:     []string{"2"},
+			input:    "a\tv10\nb\tv2\n",
+			expected: "b\tv2\na\tv10",
+		},
+		{
+			name:     "missing column",
+			comma:    ',',
+			keys:     []string{"2"},
+			input:    "a,2\nb\n",
+			expected: "b\na,2",
+		},
+		{
+			name:     "unique",
+			comma:    ',',
+			keys:     []string{"1,1"},
+			unique:   true,
+			input:    "a1,x\na2,y\na1,z\n",
+			expected: "a1,x\na2,y",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out string
+			sorter := newRecordSorter(parseKeys(t, tc.keys...), nil, tc.unique)
+			if err := performRecords(tc.comma, tc.header, sorter, strings.NewReader(tc.input), func(buf *bytes.Buffer) error {
+				out = buf.String()
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if expected, actual := tc.expected, out; expected != actual {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
+
+func parseKeys(t *testing.T, values ...string) keyList {
+	var keys keyList
+	for _, v := range values {
+		if err := keys.Set(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return keys
+}
+
+func TestKeyList(t *testing.T) {
+	t.Parallel()
+
+	keys := parseKeys(t, "3", "2,2", "1n", "4fr,5", "6,6V")
+	if expected, actual := "3 2,2 1n 4,5rf 6,6V", keys.String(); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+
+	for _, v := range []string{"0", "a", "-1", "3,1", "1x", "1nV", "1,"} {
+		if err := keys.Set(v); err == nil {
+			t.Errorf("expected error for %q", v)
+		}
+	}
+}
+
+func TestSortByKeys(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		keys      []string
+		separator string
+		unique    bool
+		input     []string
+		expected  []string
+	}{
+		{
+			name:     "second field",
+			keys:     []string{"2,2"},
+			input:    []string{"a v10", "b v9", "c v1"},
+			expected: []string{"c v1", "b v9", "a v10"},
+		},
+		{
+			name:     "priority",
+			keys:     []string{"2,2", "1,1r"},
+			input:    []string{"a x", "b y", "c x"},
+			expected: []string{"c x", "a x", "b y"},
+		},
+		{
+			name:     "numeric",
+			keys:     []string{"1n"},
+			input:    []string{"10.5 a", "-2 b", "9 c", "x d"},
+			expected: []string{"-2 b", "x d", "9 c", "10.5 a"},
+		},
+		{
+			name:     "ignore case",
+			keys:     []string{"1f"},
+			input:    []string{"beta", "Alpha", "alpha"},
+			expected: []string{"Alpha", "alpha", "beta"},
+		},
+		{
+			name:     "ignore case breaks ties",
+			keys:     []string{"1f"},
+			input:    []string{"apple", "Apple", "APPLE"},
+			expected: []string{"APPLE", "Apple", "apple"},
+		},
+		{
+			name:     "ignore case with numbers",
+			keys:     []string{"1f"},
+			input:    []string{"File10", "file9", "FILE2"},
+			expected: []string{"FILE2", "file9", "File10"},
+		},
+
+		{
+			name:     "semver",
+			keys:     []string{"2V"},
+			input:    []string{"app 1.0.0", "app 1.0.0-rc.10", "app 1.0.0-rc.2"},
+			expected: []string{"app 1.0.0-rc.2", "app 1.0.0-rc.10", "app 1.0.0"},
+		},
+		{
+			name:      "separator",
+			keys:      []string{"3,3"},
+			separator: ":",
+			input:     []string{"root:x:10", "bin:x:2"},
+			expected:  []string{"bin:x:2", "root:x:10"},
+		},
+		{
+			name:     "unique",
+			keys:     []string{"1,1"},
+			unique:   true,
+			input:    []string{"a 1", "b 2", "a 3"},
+			expected: []string{"a 1", "b 2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sorter := sortByKeys(newRecordSorter(parseKeys(t, tc.keys...), nil, tc.unique), tc.separator)
+			if expected, actual := tc.expected, sorter(tc.input); !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
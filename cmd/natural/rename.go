@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SimonRichardson/naturalsort/pkg/fs"
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRenameTemplate = ""
+	defaultRenameStart    = 1
+	defaultRenameDryRun   = false
+)
+
+// runRename renames the files in a directory into natural order safe names
+func runRename(args []string) error {
+	// flags for the rename command
+	var (
+		flagset = flag.NewFlagSet("rename", flag.ExitOnError)
+
+		template = flagset.String("template", defaultRenameTemplate, "template for new names (photo_{n:04}{ext}), zero-pads the existing names when empty")
+		start    = flagset.Int("start", defaultRenameStart, "first number used for {n}")
+		dryRun   = flagset.Bool("dryrun", defaultRenameDryRun, "print the renames without performing them")
+		manifest = flagset.String("manifest", "", "file to write an undo manifest to")
+		undo     = flagset.String("undo", "", "manifest of renames to undo")
+	)
+	flagset.Usage = usageFor(flagset, "rename [flags] [dir]")
+	if err := flagset.Parse(args); err != nil {
+		return err
+	}
+
+	dir := "."
+	if flagset.NArg() > 0 {
+		dir = flagset.Arg(0)
+	}
+
+	// Work out what we're going to rename.
+	var (
+		fsys = fs.NewRealFilesystem()
+		plan []renaming
+		err  error
+	)
+	if *undo != "" {
+		plan, err = readManifest(fsys, *undo)
+		plan = reverseRenames(plan)
+	} else {
+		plan, err = planRenames(fsys, dir, *template, *start, *manifest)
+	}
+	if err != nil {
+		return errorFor(flagset, "rename [flags] [dir]", err)
+	}
+
+	if err := checkRenames(fsys, plan); err != nil {
+		return err
+	}
+
+	if *dryRun {
+		return printRenames(os.Stdout, plan)
+	}
+
+	return performRenames(fsys, plan, *manifest)
+}
+
+// renaming is a single rename from one path to another.
+type renaming struct {
+	From, To string
+}
+
+// planRenames lists the files in dir in natural order and works out their
+// new names. Without a template the stems of the existing names are
+// zero-padded, otherwise the template is used to renumber them. Hidden files
+// and the manifest are left alone.
+func planRenames(fsys fs.Filesystem, dir, template string, start int, manifest string) ([]renaming, error) {
+	tmpl, err := parseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := fsys.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if manifest != "" && samePath(filepath.Join(dir, name), manifest) {
+			continue
+		}
+		names = append(names, name)
+	}
+	natural.Sort(names)
+
+	targets := make([]string, len(names))
+	if tmpl != nil {
+		for i, name := range names {
+			targets[i] = tmpl.execute(start+i, name)
+		}
+	} else {
+		// Only pad the stems, so numbers in extensions (`.mp4`) are kept.
+		for i, name := range names {
+			targets[i] = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		stems, err := natural.Normalize(targets)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to pad the names (dir: %q)", dir)
+		}
+		for i, name := range names {
+			targets[i] = stems[i] + filepath.Ext(name)
+		}
+	}
+
+	var plan []renaming
+	for i, name := range names {
+		if name == targets[i] {
+			continue
+		}
+		if strings.ContainsAny(targets[i], `/\`) || targets[i] == "" {
+			return nil, errors.Errorf("invalid name (name: %q, template: %q)", targets[i], template)
+		}
+		plan = append(plan, renaming{
+			From: filepath.Join(dir, name),
+			To:   filepath.Join(dir, targets[i]),
+		})
+	}
+	return plan, nil
+}
+
+// checkRenames makes sure that no two files are renamed to the same name and
+// that no existing file is overwritten.
+func checkRenames(fsys fs.Filesystem, plan []renaming) error {
+	var (
+		sources = map[string]bool{}
+		targets = map[string]string{}
+	)
+	for _, r := range plan {
+		sources[r.From] = true
+	}
+	for _, r := range plan {
+		if other, ok := targets[r.To]; ok {
+			return errors.Errorf("collision, %q and %q would both be renamed to %q", other, r.From, r.To)
+		}
+		targets[r.To] = r.From
+
+		if !sources[r.To] && fsys.Exists(r.To) {
+			return errors.Errorf("collision, %q would overwrite %q", r.From, r.To)
+		}
+	}
+	return nil
+}
+
+// applyRenames performs the renames in two steps, first to a temporary name
+// and then to the new name, so renames that swap or shift names never
+// overwrite a file that is yet to be renamed. If a rename fails, the renames
+// that were done are undone, so the files keep their original names.
+func applyRenames(fsys fs.Filesystem, plan []renaming) error {
+	var done []renaming
+	for i, r := range plan {
+		temp := tempName(fsys, r.From, i)
+		if err := fsys.Rename(r.From, temp); err != nil {
+			return rollbackRenames(fsys, done, errors.Wrapf(err, "rename %q", r.From))
+		}
+		done = append(done, renaming{From: r.From, To: temp})
+	}
+	temps := done
+	for i, r := range plan {
+		if err := fsys.Rename(temps[i].To, r.To); err != nil {
+			return rollbackRenames(fsys, done, errors.Wrapf(err, "rename %q", r.From))
+		}
+		done = append(done, renaming{From: temps[i].To, To: r.To})
+	}
+	return nil
+}
+
+// performRenames applies the renames and writes the manifest, if there is
+// one. If the manifest can't be written the renames are undone, so they're
+// never left without a record of how to undo them.
+func performRenames(fsys fs.Filesystem, plan []renaming, manifest string) error {
+	if err := applyRenames(fsys, plan); err != nil {
+		return err
+	}
+	if manifest == "" {
+		return nil
+	}
+
+	if err := writeManifest(fsys, manifest, plan); err != nil {
+		err = errors.Wrapf(err, "write manifest %q", manifest)
+		if undoErr := applyRenames(fsys, reverseRenames(plan)); undoErr != nil {
+			return errors.Wrapf(err, "undo renames failed: %v", undoErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// rollbackRenames undoes the renames that were done, in reverse, returning
+// the error that caused it along with any error undoing them.
+func rollbackRenames(fsys fs.Filesystem, done []renaming, cause error) error {
+	for _, r := range reverseRenames(done) {
+		if err := fsys.Rename(r.From, r.To); err != nil {
+			return errors.Wrapf(cause, "undo rename %q failed: %v", r.From, err)
+		}
+	}
+	return cause
+}
+
+// samePath returns if the paths refer to the same file.
+func samePath(a, b string) bool {
+	x, err := filepath.Abs(a)
+	if err != nil {
+		x = filepath.Clean(a)
+	}
+	y, err := filepath.Abs(b)
+	if err != nil {
+		y = filepath.Clean(b)
+	}
+	return x == y
+}
+
+func tempName(fsys fs.Filesystem, path string, n int) string {
+	dir, name := filepath.Split(path)
+	for i := 0; ; i++ {
+		temp := filepath.Join(dir, fmt.Sprintf(".natural-rename-%d-%d-%s", n, i, name))
+		if !fsys.Exists(temp) {
+			return temp
+		}
+	}
+}
+
+func reverseRenames(plan []renaming) []renaming {
+	res := make([]renaming, len(plan))
+	for i, r := range plan {
+		res[len(plan)-1-i] = renaming{From: r.To, To: r.From}
+	}
+	return res
+}
+
+func printRenames(w io.Writer, plan []renaming) error {
+	for _, r := range plan {
+		if _, err := fmt.Fprintf(w, "%s -> %s\n", r.From, r.To); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeManifest records the renames, one per line, so they can be undone.
+func writeManifest(fsys fs.Filesystem, path string, plan []renaming) error {
+	file, err := fsys.Create(path)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, r := range plan {
+		fmt.Fprintf(&buf, "%q\t%q\n", r.From, r.To)
+	}
+	if _, err := buf.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func readManifest(fsys fs.Filesystem, path string) ([]renaming, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		plan    []renaming
+		scanner = bufio.NewScanner(file)
+	)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 2 {
+			return nil, errors.Errorf("invalid manifest (file: %q, line: %d)", path, line)
+		}
+		from, err := strconv.Unquote(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid manifest (file: %q, line: %d)", path, line)
+		}
+		to, err := strconv.Unquote(fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid manifest (file: %q, line: %d)", path, line)
+		}
+		plan = append(plan, renaming{From: from, To: to})
+	}
+	return plan, scanner.Err()
+}
+
+var placeholderPattern = regexp.MustCompile(`\{(\w+)(?::(\d+))?\}`)
+
+// template builds new names from `{n}` (the number, optionally padded with
+// `{n:04}`), `{name}` (the original name), `{stem}` (the name without the
+// extension) and `{ext}` (the extension, including the `.`).
+type template struct {
+	source string
+}
+
+func parseTemplate(source string) (*template, error) {
+	if source == "" {
+		return nil, nil
+	}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(source, -1) {
+		switch match[1] {
+		case "n":
+		case "name", "stem", "ext":
+			if match[2] != "" {
+				return nil, errors.Errorf("unexpected width (placeholder: %q, template: %q)", match[0], source)
+			}
+		default:
+			return nil, errors.Errorf("unknown placeholder (placeholder: %q, template: %q)", match[0], source)
+		}
+	}
+	return &template{source}, nil
+}
+
+func (t *template) execute(n int, name string) string {
+	ext := filepath.Ext(name)
+	return placeholderPattern.ReplaceAllStringFunc(t.source, func(s string) string {
+		match := placeholderPattern.FindStringSubmatch(s)
+		switch match[1] {
+		case "n":
+			width, _ := strconv.Atoi(match[2])
+			return fmt.Sprintf("%0*d", width, n)
+		case "name":
+			return name
+		case "stem":
+			return strings.TrimSuffix(name, ext)
+		case "ext":
+			return ext
+		}
+		return s
+	})
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/SimonRichardson/naturalsort/pkg/fs"
+	"github.com/pkg/errors"
+)
+
+func createFiles(t *testing.T, fsys fs.Filesystem, paths ...string) {
+	for _, path := range paths {
+		file, err := fsys.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(path)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func listFiles(t *testing.T, fsys fs.Filesystem, dir string) []string {
+	infos, err := fsys.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names
+}
+
+func TestPlanRenames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero-pad", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "photos/img10.jpg", "photos/img9.jpg", "photos/img100.jpg", "photos/sub/img1.jpg")
+
+		plan, err := planRenames(fsys, "photos", "", 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []renaming{
+			{"photos/img9.jpg", "photos/img009.jpg"},
+			{"photos/img10.jpg", "photos/img010.jpg"},
+		}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, plan)
+		}
+	})
+
+	t.Run("zero-pad keeps extensions", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "videos/intro1.mp4", "videos/intro10.mp4", "videos/clip2.h264", "videos/clip10.h264", "videos/song9.mp3", "videos/song10.mp3")
+
+		plan, err := planRenames(fsys, "videos", "", 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []renaming{
+			{"videos/clip2.h264", "videos/clip02.h264"},
+			{"videos/intro1.mp4", "videos/intro01.mp4"},
+			{"videos/song9.mp3", "videos/song09.mp3"},
+		}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, plan)
+		}
+	})
+
+	t.Run("skips hidden files and the manifest", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "photos/img10.jpg", "photos/img9.jpg", "photos/.hidden1", "photos/renames10.txt")
+
+		plan, err := planRenames(fsys, "photos", "photo_{n}{ext}", 1, "photos/renames10.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []renaming{
+			{"photos/img9.jpg", "photos/photo_1.jpg"},
+			{"photos/img10.jpg", "photos/photo_2.jpg"},
+		}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, plan)
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "photos/b10.jpg", "photos/b9.png", "photos/a.jpg")
+
+		plan, err := planRenames(fsys, "photos", "photo_{n:04}{ext}", 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []renaming{
+			{"photos/b9.png", "photos/photo_0001.png"},
+			{"photos/b10.jpg", "photos/photo_0002.jpg"},
+			{"photos/a.jpg", "photos/photo_0003.jpg"},
+		}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, plan)
+		}
+	})
+
+	t.Run("template placeholders", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "dir/a.tar.gz")
+
+		plan, err := planRenames(fsys, "dir", "{n:2}-{stem}{ext}-{name}", 7, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []renaming{
+			{"dir/a.tar.gz", "dir/07-a.tar.gz-a.tar.gz"},
+		}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, plan)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "dir/a")
+
+		for _, tmpl := range []string{"{x}", "{ext:2}", "a/{n}"} {
+			if _, err := planRenames(fsys, "dir", tmpl, 1, ""); err == nil {
+				t.Errorf("expected error for %q", tmpl)
+			}
+		}
+	})
+}
+
+func TestCheckRenames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("overwrite", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "a", "b")
+
+		if err := checkRenames(fsys, []renaming{{"a", "b"}}); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("same target", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "a", "b")
+
+		if err := checkRenames(fsys, []renaming{{"a", "c"}, {"b", "c"}}); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("swap", func(t *testing.T) {
+		fsys := fs.NewVirtualFilesystem()
+		createFiles(t, fsys, "a", "b")
+
+		if err := checkRenames(fsys, []renaming{{"a", "b"}, {"b", "a"}}); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestApplyRenames(t *testing.T) {
+	t.Parallel()
+
+	fsys := fs.NewVirtualFilesystem()
+	createFiles(t, fsys, "dir/f1", "dir/f2", "dir/f3")
+
+	// Shift every file along by one, which needs the temporary names.
+	plan := []renaming{
+		{"dir/f1", "dir/f2"},
+		{"dir/f2", "dir/f3"},
+		{"dir/f3", "dir/f4"},
+	}
+	if err := checkRenames(fsys, plan); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyRenames(fsys, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := []string{"f2", "f3", "f4"}, listFiles(t, fsys, "dir"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+
+	// Make sure the contents moved with the names.
+	file, err := fsys.Open("dir/f4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(file); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := "dir/f3", buf.String(); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
+
+// failingFilesystem fails the first rename of anything to the path.
+type failingFilesystem struct {
+	fs.Filesystem
+	path   string
+	failed bool
+}
+
+func (f *failingFilesystem) Rename(oldpath, newpath string) error {
+	if newpath == f.path && !f.failed {
+		f.failed = true
+		return errors.New("failed")
+	}
+	return f.Filesystem.Rename(oldpath, newpath)
+}
+
+func TestApplyRenamesRollback(t *testing.T) {
+	t.Parallel()
+
+	plan := []renaming{
+		{"dir/f1", "dir/f2"},
+		{"dir/f2", "dir/f3"},
+		{"dir/f3", "dir/f4"},
+	}
+
+	// Fail the second step of the second rename, once the first has been
+	// done.
+	fsys := &failingFilesystem{Filesystem: fs.NewVirtualFilesystem(), path: "dir/f3"}
+	createFiles(t, fsys, "dir/f1", "dir/f2", "dir/f3")
+
+	if err := applyRenames(fsys, plan); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if expected, actual := []string{"f1", "f2", "f3"}, listFiles(t, fsys, "dir"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	for _, path := range []string{"dir/f1", "dir/f2", "dir/f3"} {
+		file, err := fsys.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(file); err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := path, buf.String(); expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	}
+}
+
+// failingWriteFilesystem creates files that fail to be written to.
+type failingWriteFilesystem struct {
+	fs.Filesystem
+}
+
+func (f failingWriteFilesystem) Create(path string) (fs.File, error) {
+	file, err := f.Filesystem.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return failingWriteFile{file}, nil
+}
+
+type failingWriteFile struct {
+	fs.File
+}
+
+func (failingWriteFile) Write(p []byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestPerformRenamesManifestFailure(t *testing.T) {
+	t.Parallel()
+
+	virtual := fs.NewVirtualFilesystem()
+	createFiles(t, virtual, "dir/f1", "dir/f2")
+
+	plan := []renaming{
+		{"dir/f1", "dir/f2"},
+		{"dir/f2", "dir/f3"},
+	}
+	fsys := failingWriteFilesystem{virtual}
+	if err := performRenames(fsys, plan, "undo"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// The renames are undone, as there's no manifest to undo them with.
+	if expected, actual := []string{"f1", "f2"}, listFiles(t, fsys, "dir"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	for _, path := range []string{"dir/f1", "dir/f2"} {
+		file, err := fsys.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(file); err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := path, buf.String(); expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	}
+}
+
+func TestManifest(t *testing.T) {
+	t.Parallel()
+
+	fsys := fs.NewVirtualFilesystem()
+	createFiles(t, fsys, "dir/a1", "dir/a10")
+
+	plan, err := planRenames(fsys, "dir", "", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := applyRenames(fsys, plan); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeManifest(fsys, "undo", plan); err != nil {
+		t.Fatal(err)
+	}
+
+	undo, err := readManifest(fsys, "undo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(undo, plan) {
+		t.Errorf("expected: %v, actual: %v", plan, undo)
+	}
+
+	undo = reverseRenames(undo)
+	if err := checkRenames(fsys, undo); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyRenames(fsys, undo); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := []string{"a1", "a10"}, listFiles(t, fsys, "dir"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestPrintRenames(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := printRenames(&buf, []renaming{{"a", "b"}, {"c", "d"}}); err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := "a -> b\nc -> d\n", buf.String(); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
@@ -7,24 +7,32 @@ import (
 	"encoding/base64"
 	"flag"
 	"io"
+	"io/ioutil"
 	"os"
+	"regexp"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/SimonRichardson/naturalsort/pkg/fs"
-	"github.com/SimonRichardson/naturalsort/pkg/group"
 	"github.com/SimonRichardson/naturalsort/pkg/natural"
-	"github.com/go-kit/kit/log"
-	"github.com/go-kit/kit/log/level"
 	"github.com/pkg/errors"
 )
 
 const (
-	defaultSeparator    = ","
-	defaultInputGzip    = false
-	defaultInputBase64  = false
-	defaultOutputGzip   = false
-	defaultOutputBase64 = false
+	defaultUnits         = false
+	defaultIP            = false
+	defaultIgnoreAccents = false
+
+	defaultPunctuation = "exact"
+	defaultZeros       = "shorter"
+	defaultUnique      = false
+	defaultCheck       = false
+	defaultCheckAll    = false
+
+	defaultHeader = false
+
+	defaultPath          = false
+	defaultPathSeparator = "/"
+	defaultPathDirsFirst = false
 )
 
 // runSort performs the sorting of the input
@@ -32,101 +40,141 @@ func runSort(args []string) error {
 	// flags for the sort command
 	var (
 		flagset = flag.NewFlagSet("sort", flag.ExitOnError)
-
-		debug     = flagset.Bool("debug", false, "debug logging")
-		separator = flagset.String("separator", defaultSeparator, "separation value")
-
-		input       = flagset.String("input", "", "input for natural sorting")
-		inputFile   = flagset.String("input.file", "", "file required to perform natural sorting on")
-		inputGzip   = flagset.Bool("input.gzip", defaultInputGzip, "decode gzip input")
-		inputBase64 = flagset.Bool("input.base64", defaultInputBase64, "decode base 64 input")
-
-		outputFile   = flagset.String("output.file", "", "output file for action performed")
-		outputGzip   = flagset.Bool("output.gzip", defaultOutputGzip, "encode gzip output")
-		outputBase64 = flagset.Bool("output.base64", defaultOutputBase64, "encode base64 output")
+		flags   = newIOFlags(flagset)
+
+		units    = flagset.Bool("units", defaultUnits, "compare sizes and durations by magnitude")
+		ip       = flagset.Bool("ip", defaultIP, "compare IP addresses and prefixes numerically")
+		articles = flagset.String("articles", "", "comma separated leading articles to ignore")
+		accents  = flagset.Bool("ignore.accents", defaultIgnoreAccents, "ignore accents, unless breaking ties")
+		zeros    = flagset.String("zeros", defaultZeros, "order of leading zeros (shorter, longer, equal)")
+		unique   = flagset.Bool("unique", defaultUnique, "remove values that are equal")
+		check    = flagset.Bool("check", defaultCheck, "check the input is sorted, rather than sorting it")
+		checkAll = flagset.Bool("check.all", defaultCheckAll, "check the input is sorted, reporting every value out of order")
+
+		punctuation      = flagset.String("punctuation", defaultPunctuation, "compare whitespace and punctuation (exact, collapse, ignore)")
+		punctuationChars = flagset.String("punctuation.chars", natural.DefaultPunctuation, "punctuation compared like whitespace")
+
+		format   = flagset.String("format", "", "sort records rather than values (csv, tsv)")
+		header   = flagset.Bool("header", defaultHeader, "keep the first record first, when sorting records")
+		fieldSep = flagset.String("field.separator", "", "separator of the fields in each value, when sorting by key, defaults to whitespace")
+		keys     keyList
+
+		path          = flagset.Bool("path", defaultPath, "compare paths one component at a time")
+		pathSeparator = flagset.String("path.separator", defaultPathSeparator, "path component separator")
+		pathDirsFirst = flagset.Bool("path.dirsfirst", defaultPathDirsFirst, "place directories before files")
 	)
+	flagset.Var(&keys, "key", "fields to sort records by (2, 2,2 or 1n), with modifiers r (reverse), f (ignore case), n (numeric) and V (semver), repeat for more keys")
 	flagset.Usage = usageFor(flagset, "sort [flags]")
 	if err := flagset.Parse(args); err != nil {
 		return err
 	}
 
-	// Setup the logger.
-	var logger log.Logger
-	{
-		logLevel := level.AllowInfo()
-		if *debug {
-			logLevel = level.AllowAll()
-		}
-		logger = log.NewLogfmtLogger(os.Stdout)
-		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
-		logger = level.NewFilter(logger, logLevel)
+	splitFn, err := flags.validate()
+	if err != nil {
+		return errorFor(flagset, "sort [flags]", err)
 	}
 
-	level.Debug(logger).Log("type", "input", "file", *inputFile, "gzip", *inputGzip)
-	level.Debug(logger).Log("type", "output", "file", *outputFile, "gzip", *outputGzip)
-
-	// Validate the separator
-	if len(*separator) != 1 {
-		return errorFor(flagset, "sort [flags]", errors.Errorf("invalid separator (separator: %q)", *separator))
+	// Work out how we're going to sort.
+	var options []natural.Option
+	if *units {
+		options = append(options, natural.WithUnits())
 	}
-
-	sepRune, size := utf8.DecodeLastRuneInString(*separator)
-	if size == 0 {
-		return errorFor(flagset, "sort [flags]", errors.Errorf("no valid separator (separator: %q)", *separator))
+	if *ip {
+		options = append(options, natural.WithIPAddresses())
+	}
+	if words := splitList(*articles); len(words) > 0 {
+		options = append(options, natural.WithArticles(words...))
+	}
+	if *accents {
+		options = append(options, natural.WithAccentFolding())
+	}
+	if mode, ok := natural.ParsePunctuation(*punctuation); !ok {
+		return errorFor(flagset, "sort [flags]", errors.Errorf("invalid punctuation (punctuation: %q)", *punctuation))
+	} else if mode != natural.PunctuationExact {
+		options = append(options, natural.WithPunctuation(mode, *punctuationChars))
+	}
+	if policy, ok := natural.ParseZeros(*zeros); !ok {
+		return errorFor(flagset, "sort [flags]", errors.Errorf("invalid zeros (zeros: %q)", *zeros))
+	} else if policy != natural.ZerosShorterFirst {
+		options = append(options, natural.WithZeros(policy))
+	}
+	if *path {
+		if *pathSeparator == "" {
+			return errorFor(flagset, "sort [flags]", errors.Errorf("no valid path separator (path.separator: %q)", *pathSeparator))
+		}
+		options = append(options, natural.WithPaths(*pathSeparator))
+		if *pathDirsFirst {
+			options = append(options, natural.WithDirectoriesFirst())
+		}
+	}
+	sep, err := unescape(*fieldSep)
+	if err != nil {
+		return errorFor(flagset, "sort [flags]", err)
 	}
-	splitFn := splitOn(sepRune)
 
-	// Validate that we either have an input or a input.file. If neither are
-	// valid then bail out.
-	in, inf := strings.TrimSpace(*input), strings.TrimSpace(*inputFile)
-	if in == "" && inf == "" {
-		return errorFor(flagset, "sort [flags]", errors.Errorf("no valid input (input: %q, file: %q)", in, inf))
+	// Work out if we're sorting records.
+	var comma rune
+	switch *format {
+	case "":
+		if *header {
+			return errorFor(flagset, "sort [flags]", errors.Errorf("header needs a format (format: %q)", *format))
+		}
+	case formatCSV:
+		comma = ','
+	case formatTSV:
+		comma = '\t'
+	default:
+		return errorFor(flagset, "sort [flags]", errors.Errorf("invalid format (format: %q)", *format))
+	}
+	if comma != 0 && (*check || *checkAll) {
+		return errorFor(flagset, "sort [flags]", errors.Errorf("check can't be used with format (format: %q)", *format))
+	}
+	if *flags.inputFormat != "" && (*check || *checkAll) {
+		return errorFor(flagset, "sort [flags]", errors.Errorf("check can't be used with input.format (input.format: %q)", *flags.inputFormat))
+	}
+	if comma != 0 && (*flags.inputFormat != "" || *flags.outputFormat != "") {
+		return errorFor(flagset, "sort [flags]", errors.Errorf("input.format and output.format can't be used with format (format: %q)", *format))
 	}
 
-	// Execution group.
-	var g group.Group
-	{
-		// Create the file system
-		fsys := fs.NewRealFilesystem()
-		g.Add(func() error {
-			// Setup how we're going to read and write.
-			reader, err := read(fsys, in, inf, *inputGzip, *inputBase64)
-			if err != nil {
-				return err
-			}
-			defer reader.Close()
+	var (
+		records = newRecordSorter(keys, options, *unique)
+		sorter  = sortWith(records.sorter, *unique)
+		compare = records.sorter.Compare
+	)
+	if len(keys) > 0 {
+		sorter = sortByKeys(records, sep)
+		compare = func(a, b string) int {
+			return records.Compare(fields(a, sep), fields(b, sep))
+		}
+	}
 
-			writer := write(fsys, *outputFile, *outputGzip, *outputBase64)
+	return flags.run(func(reader io.Reader, writer writeFn) error {
+		if comma != 0 {
+			return performRecords(comma, *header, records, reader, writer)
+		}
+		if *check || *checkAll {
+			return checkInput(splitFn, compare, *unique, *checkAll, reader, writer)
+		}
 
-			// Work out how we're going to split then join on the input.
-			iso := splitJoin{
-				Split: splitFn,
-				Join: func(x []string) string {
-					return strings.Join(x, *separator)
-				},
-			}
+		// Work out how we're going to split then join on the input.
+		iso := splitJoin{
+			Split: splitFn,
+			Join: func(x []string) string {
+				return flags.join(x)
+			},
+		}
 
-			return perform(iso, reader, writer)
-		}, func(error) {
-			// Nothing to close
-		})
-	}
-	{
-		// Setup os signal interruptions.
-		cancel := make(chan struct{})
-		g.Add(func() error {
-			return interrupt(cancel)
-		}, func(error) {
-			close(cancel)
-		})
-	}
-	return g.Run()
+		return perform(iso, sorter, reader, writer)
+	})
 }
 
 func read(fsys fs.Filesystem, input, inputFile string, inputGzip, inputBase64 bool) (reader io.ReadCloser, err error) {
 	// Read the file in a execution group, in case the file is huge.
 	// That way the cmd is still responsive for potential feedback.
-	if fsys.Exists(inputFile) {
+	if inputFile == stdinFile {
+		// Stdin is left open, as it's not ours to close.
+		reader = ioutil.NopCloser(stdin)
+	} else if fsys.Exists(inputFile) {
 		var file fs.File
 		file, err = fsys.Open(inputFile)
 		if err != nil {
@@ -161,7 +209,7 @@ func read(fsys fs.Filesystem, input, inputFile string, inputGzip, inputBase64 bo
 	return
 }
 
-func write(fsys fs.Filesystem, outputFile string, outputGzip, outputBase64 bool) writeFn {
+func write(fsys fs.Filesystem, outputFile string, outputGzip, outputBase64 bool, terminator string) writeFn {
 	return func(buf *bytes.Buffer) (err error) {
 		// Work out where to write to.
 		var writer io.Writer
@@ -192,8 +240,9 @@ func write(fsys fs.Filesystem, outputFile string, outputGzip, outputBase64 bool)
 			writer = w
 		}
 
-		// Make sure we put a new line in for some terminals
-		buf.WriteRune('\n')
+		// Make sure we put a new line in for some terminals, unless asked
+		// otherwise.
+		buf.WriteString(terminator)
 
 		// Write the output
 		_, err = buf.WriteTo(writer)
@@ -201,30 +250,14 @@ func write(fsys fs.Filesystem, outputFile string, outputGzip, outputBase64 bool)
 	}
 }
 
-func perform(iso splitJoin, reader io.Reader, writer writeFn) error {
-	// Scan everything!
-	scanner := bufio.NewScanner(reader)
-	scanner.Split(iso.Split)
-
-	var buf []string
-	for scanner.Scan() {
-		buf = append(buf, scanner.Text())
-	}
-
-	if err := scanner.Err(); err != nil {
+func perform(iso splitJoin, sorter sortFn, reader io.Reader, writer writeFn) error {
+	buf, err := scan(reader, iso.Split)
+	if err != nil {
 		return err
 	}
 
-	// Remove the last trailing `\n` of some files
-	if last := buf[len(buf)-1]; strings.ContainsRune(last, '\n') {
-		last = strings.TrimRightFunc(last, func(r rune) bool {
-			return r == '\n'
-		})
-		buf[len(buf)-1] = last
-	}
-
 	// Perform the sorting
-	natural.Sort(buf)
+	buf = sorter(buf)
 
 	// Create a buffer so that writing to sources becomes more natural
 	out := bytes.NewBufferString(iso.Join(buf))
@@ -232,24 +265,104 @@ func perform(iso splitJoin, reader io.Reader, writer writeFn) error {
 
 }
 
-func splitOn(r rune) bufio.SplitFunc {
-	if r == ' ' {
+// splitOn splits the input on every occurrence of the separator, which can be
+// any number of bytes. A separator of a single space splits on any
+// whitespace.
+func splitOn(separator string) bufio.SplitFunc {
+	if separator == " " {
 		return bufio.ScanWords
 	}
 
-	// Note this comes from the stdlib https://golang.org/src/bufio/example_test.go
+	sep := []byte(separator)
 	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-		for i := 0; i < len(data); i++ {
-			if data[i] == byte(r) {
-				return i + 1, data[:i], nil
-			}
+		if i := bytes.Index(data, sep); i >= 0 {
+			return i + len(sep), data[:i], nil
 		}
-		return 0, data, bufio.ErrFinalToken
+		if !atEOF {
+			// The separator might be split across reads, so ask for more.
+			return 0, nil, nil
+		}
+		return finalToken(data)
+	}
+}
+
+// finalToken returns the rest of the input as the last value, without the
+// trailing `\n` of some files. Input that ends with a separator has nothing
+// after it, which isn't a value.
+func finalToken(data []byte) (int, []byte, error) {
+	if token := bytes.TrimRight(data, "\n"); len(token) > 0 {
+		return len(data), token, bufio.ErrFinalToken
+	}
+	return len(data), nil, nil
+}
+
+// compileSplit compiles a pattern used for splitting, which must not match an
+// empty string, otherwise it would match everywhere.
+func compileSplit(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re.MatchString("") {
+		return nil, errors.New("matches an empty string")
+	}
+	return re, nil
+}
+
+// splitOnRegex splits the input on every match of the regular expression.
+// Note: matches are found within the input that has been read so far, so
+// matches are never longer than they would be when matching all the input.
+func splitOnRegex(re *regexp.Regexp) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		// A match that reaches the end of data might carry on, so only use it
+		// once there's more data after it.
+		if loc := re.FindIndex(data); loc != nil && (loc[1] < len(data) || atEOF) {
+			return loc[1], data[:loc[0]], nil
+		}
+		if !atEOF {
+			return 0, nil, nil
+		}
+		return finalToken(data)
+	}
+}
+
+// extractRegex yields every match of the regular expression, ignoring the
+// rest of the input.
+// Note: input without a match is dropped a line at a time, so values can
+// only span lines while they're being read.
+func extractRegex(re *regexp.Regexp) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if loc := re.FindIndex(data); loc != nil && (loc[1] < len(data) || atEOF) {
+			return loc[1], data[loc[0]:loc[1]], nil
+		} else if loc != nil {
+			return loc[0], nil, nil
+		}
+		if atEOF {
+			return len(data), nil, nil
+		}
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			return i + 1, nil, nil
+		}
+		return 0, nil, nil
 	}
 }
 
 type writeFn func(*bytes.Buffer) error
 
+type sortFn func([]string) []string
+
+// sortWith sorts using the sorter, optionally removing any values that the
+// sorter considers equal.
+func sortWith(sorter *natural.Sorter, unique bool) sortFn {
+	return func(x []string) []string {
+		if unique {
+			return sorter.Dedupe(x)
+		}
+		sorter.Sort(x)
+		return x
+	}
+}
+
 type splitJoin struct {
 	Split bufio.SplitFunc
 	Join  func([]string) string
@@ -6,12 +6,16 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
+	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"testing/quick"
 
 	"github.com/SimonRichardson/naturalsort/pkg/fs"
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
 )
 
 func TestRead(t *testing.T) {
@@ -126,6 +130,39 @@ func TestRead(t *testing.T) {
 	})
 }
 
+// TestReadStdin isn't parallel, as it replaces stdin.
+func TestReadStdin(t *testing.T) {
+	defer func(r io.Reader) { stdin = r }(stdin)
+
+	content := "0,0001,0,23,5,a3,43123"
+
+	var b bytes.Buffer
+	w := base64.NewEncoder(base64.StdEncoding, &b)
+	g := gzip.NewWriter(w)
+	if _, err := g.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	g.Close()
+	w.Close()
+	stdin = &b
+
+	fsys := fs.NewVirtualFilesystem()
+	reader, err := read(fsys, "ignored", stdinFile, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	buf, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := content, string(buf); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
+
 func TestWrite(t *testing.T) {
 	t.Parallel()
 
@@ -136,7 +173,7 @@ func TestWrite(t *testing.T) {
 
 			content := strings.Join(a, ",")
 			buf := bytes.NewBufferString(content)
-			if err := write(fsys, path, false, false)(buf); err != nil {
+			if err := write(fsys, path, false, false, "\n")(buf); err != nil {
 				t.Fatal(err)
 			}
 
@@ -168,7 +205,7 @@ func TestWrite(t *testing.T) {
 
 		content := "a,m,1,3,b,f12,12c,41,e"
 		buf := bytes.NewBufferString(content)
-		if err := write(fsys, path, true, false)(buf); err != nil {
+		if err := write(fsys, path, true, false, "\n")(buf); err != nil {
 			t.Fatal(err)
 		}
 
@@ -198,7 +235,7 @@ func TestWrite(t *testing.T) {
 
 		content := "a,m,1,3,b,f12,12c,41,e"
 		buf := bytes.NewBufferString(content)
-		if err := write(fsys, path, false, true)(buf); err != nil {
+		if err := write(fsys, path, false, true, "\n")(buf); err != nil {
 			t.Fatal(err)
 		}
 
@@ -220,12 +257,113 @@ func TestWrite(t *testing.T) {
 	})
 }
 
+func TestSplitOn(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, separator, input string
+		expected               []string
+	}{
+		{"single byte", ",", "b,a,c", []string{"b", "a", "c"}},
+		{"multi byte", " | ", "b | a | c", []string{"b", "a", "c"}},
+		{"crlf", "\r\n", "b\r\na\nc", []string{"b", "a\nc"}},
+		{"nul", "\x00", "b\x00a", []string{"b", "a"}},
+		{"unicode", "→", "b→a", []string{"b", "a"}},
+		{"trailing separator", ",", "b,a,", []string{"b", "a"}},
+		{"trailing line ending", "\n", "b\na10\na9\n", []string{"b", "a10", "a9"}},
+		{"empty values", ",", "b,,a", []string{"b", "", "a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Read a byte at a time, so separators are split across reads.
+			actual, err := scan(iotest.OneByteReader(strings.NewReader(tc.input)), splitOn(tc.separator))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := tc.expected; !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
+
+func TestSplitOnRegex(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, pattern, input string
+		expected             []string
+	}{
+		{"mixed", `[,;\s]+`, "b, a;c  d\ne", []string{"b", "a", "c", "d", "e"}},
+		{"no match", `;`, "b,a", []string{"b,a"}},
+		{"trailing separator", `,\s*`, "b, a, ", []string{"b", "a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			re, err := compileSplit(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := scan(iotest.OneByteReader(strings.NewReader(tc.input)), splitOnRegex(re))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := tc.expected; !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
+
+func TestExtractRegex(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, pattern, input string
+		expected             []string
+	}{
+		{"tickets", `[A-Z]+-\d+`, "Fixed PROJ-10 and PROJ-9.\nSee OPS-123\n", []string{"PROJ-10", "PROJ-9", "OPS-123"}},
+		{"at the end", `\d+`, "v12", []string{"12"}},
+		{"none", `\d+`, "nothing\nhere", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			re, err := compileSplit(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := scan(iotest.OneByteReader(strings.NewReader(tc.input)), extractRegex(re))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := tc.expected; !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected: %q, actual: %q", expected, actual)
+			}
+		})
+	}
+}
+
+func TestCompileSplit(t *testing.T) {
+	t.Parallel()
+
+	for _, pattern := range []string{`[`, `\s*`} {
+		if _, err := compileSplit(pattern); err == nil {
+			t.Errorf("expected error for %q", pattern)
+		}
+	}
+}
+
 func TestPerform(t *testing.T) {
 	t.Parallel()
 
 	t.Run("split words", func(t *testing.T) {
 		iso := splitJoin{
-			Split: splitOn(' '),
+			Split: splitOn(" "),
 			Join: func(x []string) string {
 				return strings.Join(x, " ")
 			},
@@ -237,7 +375,7 @@ func TestPerform(t *testing.T) {
 			writer  bytes.Buffer
 		)
 
-		if err := perform(iso, reader, func(b *bytes.Buffer) error {
+		if err := perform(iso, sortWith(natural.NewSorter(), false), reader, func(b *bytes.Buffer) error {
 			writer.Write(b.Bytes())
 			return nil
 		}); err != nil {
@@ -256,7 +394,7 @@ func TestPerform(t *testing.T) {
 
 	t.Run("split comma", func(t *testing.T) {
 		iso := splitJoin{
-			Split: splitOn(','),
+			Split: splitOn(","),
 			Join: func(x []string) string {
 				return strings.Join(x, ",")
 			},
@@ -268,7 +406,7 @@ func TestPerform(t *testing.T) {
 			writer  bytes.Buffer
 		)
 
-		if err := perform(iso, reader, func(b *bytes.Buffer) error {
+		if err := perform(iso, sortWith(natural.NewSorter(), false), reader, func(b *bytes.Buffer) error {
 			writer.Write(b.Bytes())
 			return nil
 		}); err != nil {
@@ -287,7 +425,7 @@ func TestPerform(t *testing.T) {
 
 	t.Run("split newline", func(t *testing.T) {
 		iso := splitJoin{
-			Split: splitOn('\n'),
+			Split: splitOn("\n"),
 			Join: func(x []string) string {
 				return strings.Join(x, "\n")
 			},
@@ -299,19 +437,73 @@ func TestPerform(t *testing.T) {
 			writer  bytes.Buffer
 		)
 
-		if err := perform(iso, reader, func(b *bytes.Buffer) error {
+		if err := perform(iso, sortWith(natural.NewSorter(), false), reader, func(b *bytes.Buffer) error {
 			writer.Write(b.Bytes())
 			return nil
 		}); err != nil {
 			t.Fatal(err)
 		}
 
-		r := make([]byte, len(content))
-		if _, err := io.ReadFull(&writer, r); err != nil {
+		// The trailing separator doesn't add an empty value.
+		if expected, actual := "a\nb\nc\nd\ne", writer.String(); expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	})
+
+	t.Run("trailing separator", func(t *testing.T) {
+		for _, sep := range []string{",", "\n", "\r\n", ";;"} {
+			iso := splitJoin{
+				Split: splitOn(sep),
+				Join: func(x []string) string {
+					return strings.Join(x, sep)
+				},
+			}
+
+			var (
+				content = strings.Join([]string{"b", "", "a10", "a9", ""}, sep)
+				reader  = bytes.NewBufferString(content)
+				writer  bytes.Buffer
+			)
+
+			if err := perform(iso, sortWith(natural.NewSorter(), false), reader, func(b *bytes.Buffer) error {
+				writer.Write(b.Bytes())
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			// Empty values in the middle are kept, only the one after the
+			// final separator is dropped.
+			expected := strings.Join([]string{"", "a9", "a10", "b"}, sep)
+			if actual := writer.String(); expected != actual {
+				t.Errorf("expected: %q, actual: %q (separator: %q)", expected, actual, sep)
+			}
+		}
+	})
+
+	t.Run("unique", func(t *testing.T) {
+		iso := splitJoin{
+			Split: splitOn(","),
+			Join: func(x []string) string {
+				return strings.Join(x, ",")
+			},
+		}
+
+		var (
+			content = "a1,a01,a2,a001"
+			reader  = bytes.NewBufferString(content)
+			writer  bytes.Buffer
+		)
+
+		sorter := natural.NewSorter(natural.WithZeros(natural.ZerosEqual))
+		if err := perform(iso, sortWith(sorter, true), reader, func(b *bytes.Buffer) error {
+			writer.Write(b.Bytes())
+			return nil
+		}); err != nil {
 			t.Fatal(err)
 		}
 
-		if expected, actual := "\na\nb\nc\nd\ne", string(r); expected != actual {
+		if expected, actual := "a1,a2", writer.String(); expected != actual {
 			t.Errorf("expected: %q, actual: %q", expected, actual)
 		}
 	})
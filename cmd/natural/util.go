@@ -1,11 +1,15 @@
 package main
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+
+	"github.com/pkg/errors"
 )
 
 func interrupt(cancel <-chan struct{}) error {
@@ -18,3 +22,55 @@ func interrupt(cancel <-chan struct{}) error {
 		return errors.New("canceled")
 	}
 }
+
+// splitList splits a comma separated flag value, dropping any empty values.
+func splitList(s string) []string {
+	var res []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// unescape replaces the escape sequences `\n`, `\r`, `\t`, `\0`, `\\` and
+// `\xHH` in s, so separators such as newlines can be given from a shell.
+func unescape(s string) (string, error) {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			buf.WriteByte(s[i])
+			continue
+		}
+		if i++; i == len(s) {
+			return "", errors.Errorf("invalid escape (value: %q)", s)
+		}
+
+		switch s[i] {
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case '0':
+			buf.WriteByte(0)
+		case '\\':
+			buf.WriteByte('\\')
+		case 'x':
+			if i+2 >= len(s) {
+				return "", errors.Errorf("invalid escape (value: %q)", s)
+			}
+			b, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", errors.Errorf("invalid escape (value: %q)", s)
+			}
+			buf.WriteByte(byte(b))
+			i += 2
+		default:
+			return "", errors.Errorf("invalid escape (value: %q)", s)
+		}
+	}
+	return buf.String(), nil
+}
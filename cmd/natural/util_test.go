@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestUnescape(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input, expected string
+	}{
+		{",", ","},
+		{" | ", " | "},
+		{`\n`, "\n"},
+		{`\r\n`, "\r\n"},
+		{`\t`, "\t"},
+		{`\0`, "\x00"},
+		{`\x1f`, "\x1f"},
+		{`a\\b`, `a\b`},
+	}
+
+	for _, tc := range testCases {
+		actual, err := unescape(tc.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := tc.expected; expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	}
+
+	for _, input := range []string{`\`, `\q`, `\x1`, `\xzz`} {
+		if _, err := unescape(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
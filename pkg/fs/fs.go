@@ -2,12 +2,15 @@ package fs
 
 import (
 	"io"
+	"os"
 )
 
 type Filesystem interface {
 	Create(path string) (File, error)
 	Open(path string) (File, error)
 	Exists(path string) bool
+	ReadDir(path string) ([]os.FileInfo, error)
+	Rename(oldpath, newpath string) error
 }
 
 type File interface {
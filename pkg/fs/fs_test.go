@@ -72,6 +72,62 @@ func TestReal(t *testing.T) {
 			t.Errorf("expected: %v, actual: %v", content, buf)
 		}
 	})
+
+	t.Run("read dir", func(t *testing.T) {
+		sub, err := ioutil.TempDir(dir, "readdir")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fsys := NewRealFilesystem()
+		for _, name := range []string{"b", "a"} {
+			file, err := fsys.Create(filepath.Join(sub, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.Close()
+		}
+		if err := os.Mkdir(filepath.Join(sub, "c"), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		infos, err := fsys.ReadDir(sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, info := range infos {
+			names = append(names, info.Name())
+		}
+		if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(expected, names) {
+			t.Errorf("expected: %v, actual: %v", expected, names)
+		}
+		if !infos[2].IsDir() {
+			t.Errorf("expected: %q to be a directory", infos[2].Name())
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		fsys := NewRealFilesystem()
+		from, to := filepath.Join(dir, "rename-from"), filepath.Join(dir, "rename-to")
+		file, err := fsys.Create(from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Close()
+
+		if err := fsys.Rename(from, to); err != nil {
+			t.Fatal(err)
+		}
+
+		if fsys.Exists(from) {
+			t.Errorf("expected: %q to not exist", from)
+		}
+		if !fsys.Exists(to) {
+			t.Errorf("expected: %q to exist", to)
+		}
+	})
 }
 
 func TestVirtual(t *testing.T) {
@@ -120,4 +176,97 @@ func TestVirtual(t *testing.T) {
 			t.Errorf("expected: %v, actual: %v", content, buf)
 		}
 	})
+
+	t.Run("open twice", func(t *testing.T) {
+		fsys := NewVirtualFilesystem()
+		tmpfile, err := fsys.Create("tmpfile")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content := []byte("hello world")
+		if _, err := tmpfile.Write(content); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 2; i++ {
+			file, err := fsys.Open("tmpfile")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buf, err := ioutil.ReadAll(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.Close()
+
+			if !reflect.DeepEqual(content, buf) {
+				t.Errorf("expected: %v, actual: %v", content, buf)
+			}
+		}
+	})
+
+	t.Run("read dir", func(t *testing.T) {
+		fsys := NewVirtualFilesystem()
+		for _, path := range []string{"dir/b", "dir/a", "dir/c/d", "other"} {
+			file, err := fsys.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := file.Write([]byte(path)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		infos, err := fsys.ReadDir("dir")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, info := range infos {
+			names = append(names, info.Name())
+		}
+		if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(expected, names) {
+			t.Errorf("expected: %v, actual: %v", expected, names)
+		}
+		if !infos[2].IsDir() {
+			t.Errorf("expected: %q to be a directory", infos[2].Name())
+		}
+		if expected, actual := int64(len("dir/a")), infos[0].Size(); expected != actual {
+			t.Errorf("expected: %d, actual: %d", expected, actual)
+		}
+
+		if _, err := fsys.ReadDir("missing"); !os.IsNotExist(err) {
+			t.Errorf("expected: not exist error, actual: %v", err)
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		fsys := NewVirtualFilesystem()
+		for _, path := range []string{"from", "dir/a"} {
+			if _, err := fsys.Create(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := fsys.Rename("from", "to"); err != nil {
+			t.Fatal(err)
+		}
+		if fsys.Exists("from") || !fsys.Exists("to") {
+			t.Errorf("expected: %q to be renamed to %q", "from", "to")
+		}
+
+		if err := fsys.Rename("dir", "moved"); err != nil {
+			t.Fatal(err)
+		}
+		if fsys.Exists("dir/a") || !fsys.Exists("moved/a") {
+			t.Errorf("expected: %q to be renamed to %q", "dir", "moved")
+		}
+
+		if err := fsys.Rename("missing", "other"); !os.IsNotExist(err) {
+			t.Errorf("expected: not exist error, actual: %v", err)
+		}
+	})
 }
@@ -2,6 +2,7 @@ package fs
 
 import (
 	"io"
+	"io/ioutil"
 	"os"
 )
 
@@ -45,6 +46,14 @@ func (realFilesystem) Exists(path string) bool {
 	return !os.IsNotExist(err)
 }
 
+func (realFilesystem) ReadDir(path string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(path)
+}
+
+func (realFilesystem) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
 type realFile struct {
 	*os.File
 	io.Reader
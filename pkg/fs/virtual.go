@@ -3,7 +3,11 @@ package fs
 import (
 	"bytes"
 	"os"
+	"path"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
 type virtualFilesystem struct {
@@ -23,9 +27,10 @@ func (v *virtualFilesystem) Create(path string) (File, error) {
 	defer v.mutex.Unlock()
 	// os.Create truncates any existing files. So we do, too.
 	f := &virtualFile{
-		name: path,
+		name:    path,
+		modTime: time.Now(),
 	}
-	v.files[path] = f
+	v.files[clean(path)] = f
 	return f, nil
 }
 
@@ -33,25 +38,109 @@ func (v *virtualFilesystem) Open(path string) (File, error) {
 	v.mutex.Lock()
 	defer v.mutex.Unlock()
 
-	f, ok := v.files[path]
+	f, ok := v.files[clean(path)]
 	if !ok {
 		return nil, os.ErrNotExist
 	}
-	return f, nil
+	// Every time a file is opened it's read from the start, like os.Open.
+	return &virtualReader{f, bytes.NewReader(f.bytes())}, nil
 }
 
 func (v *virtualFilesystem) Exists(path string) bool {
 	v.mutex.Lock()
 	defer v.mutex.Unlock()
 
-	_, ok := v.files[path]
-	return ok
+	p := clean(path)
+	if _, ok := v.files[p]; ok {
+		return true
+	}
+	// Directories only exist while they have files in them.
+	return v.isDir(p)
+}
+
+func (v *virtualFilesystem) ReadDir(dir string) ([]os.FileInfo, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	prefix := clean(dir) + "/"
+	if prefix == "./" {
+		prefix = ""
+	}
+
+	var (
+		res  []os.FileInfo
+		dirs = map[string]bool{}
+	)
+	for p, f := range v.files {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+
+		// Anything further down the tree means there is a directory.
+		rel := p[len(prefix):]
+		if pos := strings.IndexByte(rel, '/'); pos != -1 {
+			if name := rel[:pos]; !dirs[name] {
+				dirs[name] = true
+				res = append(res, virtualFileInfo{name: name, dir: true, modTime: f.modTime})
+			}
+			continue
+		}
+		res = append(res, f.info(rel))
+	}
+	if len(res) == 0 && prefix != "" {
+		return nil, &os.PathError{Op: "readdir", Path: dir, Err: os.ErrNotExist}
+	}
+
+	// Match ioutil.ReadDir, which sorts by name.
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Name() < res[b].Name()
+	})
+	return res, nil
+}
+
+func (v *virtualFilesystem) Rename(oldpath, newpath string) error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	from, to := clean(oldpath), clean(newpath)
+	if f, ok := v.files[from]; ok {
+		delete(v.files, from)
+		f.name = newpath
+		v.files[to] = f
+		return nil
+	}
+
+	// Move everything inside a directory.
+	if !v.isDir(from) {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: os.ErrNotExist}
+	}
+	for p, f := range v.files {
+		if strings.HasPrefix(p, from+"/") {
+			delete(v.files, p)
+			v.files[to+p[len(from):]] = f
+		}
+	}
+	return nil
+}
+
+func (v *virtualFilesystem) isDir(p string) bool {
+	for name := range v.files {
+		if strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func clean(p string) string {
+	return path.Clean(p)
 }
 
 type virtualFile struct {
-	name  string
-	mutex sync.Mutex
-	buf   bytes.Buffer
+	name    string
+	modTime time.Time
+	mutex   sync.Mutex
+	buf     bytes.Buffer
 }
 
 func (v *virtualFile) Read(p []byte) (int, error) {
@@ -65,7 +154,56 @@ func (v *virtualFile) Write(p []byte) (int, error) {
 	v.mutex.Lock()
 	defer v.mutex.Unlock()
 
+	v.modTime = time.Now()
 	return v.buf.Write(p)
 }
 
 func (v *virtualFile) Close() error { return nil }
+
+func (v *virtualFile) bytes() []byte {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	return append([]byte(nil), v.buf.Bytes()...)
+}
+
+func (v *virtualFile) info(name string) os.FileInfo {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	return virtualFileInfo{
+		name:    name,
+		size:    int64(v.buf.Len()),
+		modTime: v.modTime,
+	}
+}
+
+// virtualReader reads the contents of a file as they were when it was opened.
+type virtualReader struct {
+	*virtualFile
+	reader *bytes.Reader
+}
+
+func (v *virtualReader) Read(p []byte) (int, error) {
+	return v.reader.Read(p)
+}
+
+type virtualFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i virtualFileInfo) Name() string       { return i.name }
+func (i virtualFileInfo) Size() int64        { return i.size }
+func (i virtualFileInfo) ModTime() time.Time { return i.modTime }
+func (i virtualFileInfo) IsDir() bool        { return i.dir }
+func (i virtualFileInfo) Sys() interface{}   { return nil }
+
+func (i virtualFileInfo) Mode() os.FileMode {
+	if i.dir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
@@ -0,0 +1,47 @@
+package natural
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Articles holds the leading articles for a few common languages, keyed by
+// their ISO 639-1 code. They can be passed to WithArticles, for example
+// `WithArticles(Articles["en"]...)`.
+var Articles = map[string][]string{
+	"de": {"der", "die", "das", "ein", "eine"},
+	"en": {"the", "a", "an"},
+	"es": {"el", "la", "los", "las", "un", "una"},
+	"fr": {"le", "la", "les", "l'", "un", "une"},
+	"it": {"il", "lo", "la", "i", "gli", "le", "l'", "un", "una"},
+	"nl": {"de", "het", "een"},
+	"pt": {"o", "a", "os", "as", "um", "uma"},
+}
+
+// stripArticle removes the first of the articles found at the start of s.
+// Articles are matched regardless of case and must be followed by
+// whitespace, unless they end with an apostrophe (`l'`).
+func stripArticle(s string, articles []string) string {
+	for _, article := range articles {
+		n := len(article)
+		if n == 0 || n >= len(s) || !strings.EqualFold(s[:n], article) {
+			continue
+		}
+
+		rest := s[n:]
+		if last, _ := utf8.DecodeLastRuneInString(article); last != '\'' && last != '’' {
+			trimmed := strings.TrimLeftFunc(rest, unicode.IsSpace)
+			if len(trimmed) == len(rest) {
+				continue
+			}
+			rest = trimmed
+		}
+
+		// Don't strip the article if it's all there is.
+		if rest != "" {
+			return rest
+		}
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortArticles(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		articles         []string
+		actual, expected []string
+	}{
+		{
+			"english",
+			Articles["en"],
+			[]string{"The Matrix", "Zoolander", "A Bug's Life", "Memento", "An Affair"},
+			[]string{"An Affair", "A Bug's Life", "The Matrix", "Memento", "Zoolander"},
+		},
+		{
+			"ignores case",
+			Articles["en"],
+			[]string{"the Matrix 10", "THE Matrix 9"},
+			[]string{"THE Matrix 9", "the Matrix 10"},
+		},
+		{
+			"ties fallback to the full string",
+			Articles["en"],
+			[]string{"The Matrix", "Matrix"},
+			[]string{"Matrix", "The Matrix"},
+		},
+		{
+			"whole words only",
+			Articles["en"],
+			[]string{"Theory", "Anchor", "Bee"},
+			[]string{"Anchor", "Bee", "Theory"},
+		},
+		{
+			"article only",
+			Articles["en"],
+			[]string{"The", "B", "A"},
+			[]string{"A", "B", "The"},
+		},
+		{
+			"elision",
+			Articles["fr"],
+			[]string{"L'Avventura", "Le Mans", "Bonjour"},
+			[]string{"L'Avventura", "Bonjour", "Le Mans"},
+		},
+		{
+			"custom",
+			[]string{"Das", "Der"},
+			[]string{"Der Zug", "Das Auto", "Bahn"},
+			[]string{"Das Auto", "Bahn", "Der Zug"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			NewSorter(WithArticles(tc.articles...)).Sort(tc.actual)
+
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
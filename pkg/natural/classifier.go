@@ -0,0 +1,19 @@
+package natural
+
+// Classifier recognises a type of value inside strings and knows how to
+// compare them, so values that aren't just text and digits can be sorted
+// naturally.
+//
+// Values are only matched at the start of a word, where a word is a run of
+// letters and digits, and must end at the end of a word. Values found by
+// different classifiers, or that are plain digits, are ranked by the order
+// the classifiers were given to the Sorter, with plain digits first.
+type Classifier interface {
+	// Match returns the length in bytes of the value at the start of s, or 0
+	// if there isn't one. Lengths longer than s are ignored.
+	Match(s string) int
+
+	// Compare returns -1, 0 or 1 depending on if a sorts before, the same as
+	// or after b. Both a and b will have been matched by the Classifier.
+	Compare(a, b string) int
+}
@@ -0,0 +1,85 @@
+package natural_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SimonRichardson/naturalsort/pkg/natural"
+)
+
+// tickets recognises ticket IDs such as `PROJ-123`, comparing them by their
+// project and then their number.
+type tickets struct{}
+
+var ticketPattern = regexp.MustCompile(`^[A-Z]+-\d+`)
+
+func (tickets) Match(s string) int {
+	return len(ticketPattern.FindString(s))
+}
+
+func (tickets) Compare(a, b string) int {
+	x, y := strings.SplitN(a, "-", 2), strings.SplitN(b, "-", 2)
+	if c := strings.Compare(x[0], y[0]); c != 0 {
+		return c
+	}
+	xNum, _ := strconv.Atoi(x[1])
+	yNum, _ := strconv.Atoi(y[1])
+	switch {
+	case xNum < yNum:
+		return -1
+	case xNum > yNum:
+		return 1
+	}
+	return 0
+}
+
+func ExampleWithClassifier_tickets() {
+	input := []string{"fix OPS-2", "fix PROJ-123", "fix PROJ-45", "fix OPS-10"}
+
+	sorter := natural.NewSorter(natural.WithClassifier(tickets{}))
+	sorter.Sort(input)
+
+	fmt.Println(strings.Join(input, ", "))
+	// Output: fix OPS-2, fix OPS-10, fix PROJ-45, fix PROJ-123
+}
+
+// chromosomes recognises chromosomes such as `chr7`, placing the sex
+// chromosomes (`chrX`, `chrY`) and mitochondrial DNA (`chrM`) after `chr22`.
+type chromosomes struct{}
+
+var chromosomePattern = regexp.MustCompile(`^chr(\d+|X|Y|M)`)
+
+func (chromosomes) Match(s string) int {
+	return len(chromosomePattern.FindString(s))
+}
+
+func (chromosomes) Compare(a, b string) int {
+	x, y := chromosomeRank(a), chromosomeRank(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func chromosomeRank(s string) int {
+	name := strings.TrimPrefix(s, "chr")
+	if n, err := strconv.Atoi(name); err == nil {
+		return n
+	}
+	return 1000 + strings.Index("XYM", name)
+}
+
+func ExampleWithClassifier_chromosomes() {
+	input := []string{"chrY:100", "chr2:5", "chrX:7", "chr22:1", "chrM:3", "chr10:9"}
+
+	sorter := natural.NewSorter(natural.WithClassifier(chromosomes{}))
+	sorter.Sort(input)
+
+	fmt.Println(strings.Join(input, ", "))
+	// Output: chr2:5, chr10:9, chr22:1, chrX:7, chrY:100, chrM:3
+}
@@ -0,0 +1,29 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+// overreach is a misbehaving classifier, that matches past the end of the
+// string.
+type overreach struct{}
+
+func (overreach) Match(s string) int {
+	return len(s) + 10
+}
+
+func (overreach) Compare(a, b string) int {
+	return 0
+}
+
+func TestSortMisbehavingClassifier(t *testing.T) {
+	t.Parallel()
+
+	actual := []string{"a10", "a9", "", "b"}
+	NewSorter(WithClassifier(overreach{})).Sort(actual)
+
+	if expected := []string{"", "a9", "a10", "b"}; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,299 @@
+package natural
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Compact shortens runs of items that only differ by their last number into
+// range notation, the same way Slurm and pdsh write hostlists, so `host1`,
+// `host2`, `host3` and `host5` become `host[1-3,5]`. Zero-padding is kept,
+// so `host01` to `host10` becomes `host[01-10]`.
+// Only neighbouring items are merged, so the order of the items is kept, and
+// the characters used by the notation (`[`, `]`, `,` and `\`) are escaped with
+// a backslash, so Expand returns the original items.
+func Compact(items []string) []string {
+	var (
+		res []string
+		cur *hostlist
+	)
+	for _, item := range items {
+		nums := numbers(item)
+		if len(nums) == 0 {
+			cur = nil
+			res = append(res, escape(item))
+			continue
+		}
+
+		n := nums[len(nums)-1]
+		prefix, digits, suffix := item[:n.pos], item[n.pos:n.end], item[n.end:]
+		value, err := strconv.Atoi(digits)
+		if err != nil {
+			cur = nil
+			res = append(res, escape(item))
+			continue
+		}
+
+		if cur != nil && cur.prefix == prefix && cur.suffix == suffix {
+			cur.add(value, digits)
+			res[len(res)-1] = cur.String()
+			continue
+		}
+
+		cur = &hostlist{prefix: prefix, suffix: suffix}
+		cur.add(value, digits)
+		res = append(res, cur.String())
+	}
+	return res
+}
+
+// maxExpand is the most items that Expand returns, so a typo such as
+// `host[0-99999999999]` is an error rather than running out of memory.
+const maxExpand = 1 << 20
+
+// Expand turns range notation back into the individual items, so
+// `host[01-03]` becomes `host01`, `host02` and `host03`. Several comma
+// separated expressions can be given, along with several ranges in one
+// expression, such as `rack[1-2]-node[1-3]`. A backslash escapes the character
+// after it, so `a\[1\]` is the single item `a[1]`.
+func Expand(s string) ([]string, error) {
+	var res []string
+	for _, expr := range splitOutsideBrackets(s) {
+		items, err := expand(expr)
+		if err != nil {
+			return nil, err
+		}
+		if len(res)+len(items) > maxExpand {
+			return nil, errors.Errorf("too many items, expected at most %d (expression: %q)", maxExpand, s)
+		}
+		res = append(res, items...)
+	}
+	return res, nil
+}
+
+func expand(expr string) ([]string, error) {
+	open := indexUnescaped(expr, '[')
+	if open == -1 {
+		if indexUnescaped(expr, ']') != -1 {
+			return nil, errors.Errorf("unexpected ] (expression: %q)", expr)
+		}
+		return []string{unescape(expr)}, nil
+	}
+	closing := indexUnescaped(expr[open:], ']')
+	if closing == -1 {
+		return nil, errors.Errorf("missing ] (expression: %q)", expr)
+	}
+	closing += open
+
+	// Expand the remainder first, so every item in the range can be combined
+	// with it.
+	rest, err := expand(expr[closing+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res    []string
+		prefix = unescape(expr[:open])
+	)
+	for _, part := range strings.Split(expr[open+1:closing], ",") {
+		values, err := expandRange(part)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid range (expression: %q)", expr)
+		}
+		if len(res)+len(values)*len(rest) > maxExpand {
+			return nil, errors.Errorf("too many items, expected at most %d (expression: %q)", maxExpand, expr)
+		}
+		for _, value := range values {
+			for _, r := range rest {
+				res = append(res, prefix+value+r)
+			}
+		}
+	}
+	return res, nil
+}
+
+// expandRange expands `01-03` into `01`, `02` and `03`. The width of the
+// first number is used for every number.
+func expandRange(part string) ([]string, error) {
+	from, to := part, part
+	if pos := strings.IndexByte(part, '-'); pos != -1 {
+		from, to = part[:pos], part[pos+1:]
+	}
+	if !isASCIIDigits(from) || !isASCIIDigits(to) {
+		return nil, errors.Errorf("expected a number or range of numbers, got %q", part)
+	}
+
+	x, err := strconv.Atoi(from)
+	if err != nil {
+		return nil, err
+	}
+	y, err := strconv.Atoi(to)
+	if err != nil {
+		return nil, err
+	}
+	if x > y {
+		return nil, errors.Errorf("range is backwards %q", part)
+	}
+	if y-x >= maxExpand {
+		return nil, errors.Errorf("range is too large %q, expected at most %d numbers", part, maxExpand)
+	}
+
+	res := make([]string, 0, y-x+1)
+	for i := x; i <= y; i++ {
+		res = append(res, pad(i, len(from)))
+	}
+	return res, nil
+}
+
+// splitOutsideBrackets splits s on any commas that aren't inside brackets or
+// escaped.
+func splitOutsideBrackets(s string) []string {
+	var (
+		res   []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(res, s[start:])
+}
+
+// notation are the characters that have a meaning in range notation.
+const notation = `\[],`
+
+// escape escapes the characters in s that have a meaning in range notation.
+func escape(s string) string {
+	if !strings.ContainsAny(s, notation) {
+		return s
+	}
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(notation, s[i]) != -1 {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(s[i])
+	}
+	return buf.String()
+}
+
+// unescape removes the backslashes added by escape.
+func unescape(s string) string {
+	if strings.IndexByte(s, '\\') == -1 {
+		return s
+	}
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		buf.WriteByte(s[i])
+	}
+	return buf.String()
+}
+
+// indexUnescaped returns the index of the first c in s that isn't escaped, or
+// -1 if there isn't one.
+func indexUnescaped(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case c:
+			return i
+		}
+	}
+	return -1
+}
+
+// hostlist is a prefix and suffix, with a list of ranges in between.
+type hostlist struct {
+	prefix, suffix string
+	ranges         []hostRange
+}
+
+// hostRange is a run of consecutive numbers that share a width.
+type hostRange struct {
+	from, to int
+	width    int
+
+	// fixed is set once a zero-padded number is added, which means the
+	// width can't change.
+	fixed bool
+}
+
+func (h *hostlist) add(value int, digits string) {
+	padded := len(digits) > 1 && digits[0] == '0'
+	if n := len(h.ranges); n > 0 {
+		r := &h.ranges[n-1]
+		if r.to+1 == value && r.fits(digits, padded) {
+			r.to = value
+			return
+		}
+	}
+
+	width := 1
+	if padded {
+		width = len(digits)
+	}
+	h.ranges = append(h.ranges, hostRange{value, value, width, padded})
+}
+
+// fits returns if the digits can be written using the width of the range.
+func (r hostRange) fits(digits string, padded bool) bool {
+	if padded {
+		// Numbers without padding can't be written at a wider width, so only
+		// ranges that are already padded to the same width fit.
+		return r.fixed && r.width == len(digits)
+	}
+	return r.width <= len(digits)
+}
+
+func (h *hostlist) String() string {
+	if len(h.ranges) == 1 && h.ranges[0].from == h.ranges[0].to {
+		r := h.ranges[0]
+		return escape(h.prefix) + pad(r.from, r.width) + escape(h.suffix)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(escape(h.prefix))
+	buf.WriteByte('[')
+	for i, r := range h.ranges {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(pad(r.from, r.width))
+		if r.from != r.to {
+			buf.WriteByte('-')
+			buf.WriteString(pad(r.to, r.width))
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(escape(h.suffix))
+	return buf.String()
+}
+
+// pad formats n with leading zeros, up to the width.
+func pad(n, width int) string {
+	s := strconv.Itoa(n)
+	if len(s) < width {
+		s = strings.Repeat("0", width-len(s)) + s
+	}
+	return s
+}
@@ -0,0 +1,162 @@
+package natural
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		items    []string
+		expected []string
+	}{
+		{
+			"ranges",
+			[]string{"host1", "host2", "host3", "host5", "host7", "host8"},
+			[]string{"host[1-3,5,7-8]"},
+		},
+		{
+			"zero-padding",
+			[]string{"host01", "host02", "host03", "host04", "host05", "host06", "host07", "host08", "host09", "host10"},
+			[]string{"host[01-10]"},
+		},
+		{
+			"mixed padding",
+			[]string{"host9", "host010", "host011", "host12"},
+			[]string{"host[9,010-011,12]"},
+		},
+		{
+			"unpadded widths",
+			[]string{"host8", "host9", "host10"},
+			[]string{"host[8-10]"},
+		},
+		{
+			"single item",
+			[]string{"host05"},
+			[]string{"host05"},
+		},
+		{
+			"suffixes",
+			[]string{"rack1-node1.example.com", "rack1-node2.example.com", "rack2-node1.example.com"},
+			[]string{"rack1-node[1-2].example.com", "rack2-node1.example.com"},
+		},
+		{
+			"duplicates",
+			[]string{"host1", "host2", "host2"},
+			[]string{"host[1-2,2]"},
+		},
+		{
+			"no numbers",
+			[]string{"alpha", "host1", "host2", "beta"},
+			[]string{"alpha", "host[1-2]", "beta"},
+		},
+		{
+			"notation characters",
+			[]string{"a[1],b1", "a[1],b2", "x,y", `c:\tmp`, "[9]"},
+			[]string{`a\[1\]\,b[1-2]`, `x\,y`, `c:\\tmp`, `\[9\]`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := Compact(tc.items)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, actual)
+			}
+
+			// Make sure the compacted form round trips.
+			var expanded []string
+			for _, expr := range actual {
+				items, err := Expand(expr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expanded = append(expanded, items...)
+			}
+			if !reflect.DeepEqual(expanded, tc.items) {
+				t.Errorf("expected: %v, actual: %v", tc.items, expanded)
+			}
+
+			// Including when the expressions are joined into one list.
+			joined, err := Expand(strings.Join(actual, ","))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(joined, tc.items) {
+				t.Errorf("expected: %v, actual: %v", tc.items, joined)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		expr     string
+		expected []string
+	}{
+		{
+			"zero-padding",
+			"host[01-03]",
+			[]string{"host01", "host02", "host03"},
+		},
+		{
+			"lists",
+			"host[1-2,5,7-8]",
+			[]string{"host1", "host2", "host5", "host7", "host8"},
+		},
+		{
+			"several expressions",
+			"host[1-2],gpu[01-02].local,login",
+			[]string{"host1", "host2", "gpu01.local", "gpu02.local", "login"},
+		},
+		{
+			"several ranges",
+			"rack[1-2]-node[1-2]",
+			[]string{"rack1-node1", "rack1-node2", "rack2-node1", "rack2-node2"},
+		},
+		{
+			"escapes",
+			`a\[1\]\,b[1-2],x\,y`,
+			[]string{"a[1],b1", "a[1],b2", "x,y"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := Expand(tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		"host[1-2",
+		"host1-2]",
+		"host[a-b]",
+		"host[3-1]",
+		"host[0-99999999999999]",
+		"host[0-99999999999999999999]",
+		"rack[1-2000]-node[1-2000]",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Expand(expr); err == nil {
+				t.Errorf("expected error for %q", expr)
+			}
+		})
+	}
+}
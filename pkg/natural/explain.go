@@ -0,0 +1,119 @@
+package natural
+
+// Rule is the rule that decided how two strings compare.
+type Rule int
+
+const (
+	// RuleEqual is used when the strings are the same.
+	RuleEqual Rule = iota
+
+	// RuleEmpty is used when only one of the strings is empty, which always
+	// sorts first.
+	RuleEmpty
+
+	// RuleText is used when the text before a value, or at the end of the
+	// strings, is different.
+	RuleText
+
+	// RuleMissingValue is used when only one of the strings has a value, the
+	// string with the value sorts first.
+	RuleMissingValue
+
+	// RuleClass is used when the values were recognised by different
+	// classifiers, which are ranked by the order they're checked in.
+	RuleClass
+
+	// RuleNumber is used when the numbers have different values.
+	RuleNumber
+
+	// RuleZeros is used when the numbers have the same value and only differ
+	// by their leading zeros, which are ordered by the Zeros policy.
+	RuleZeros
+
+	// RuleClassifier is used when a Classifier compared the values.
+	RuleClassifier
+)
+
+func (r Rule) String() string {
+	switch r {
+	case RuleEqual:
+		return "equal"
+	case RuleEmpty:
+		return "empty string"
+	case RuleText:
+		return "text"
+	case RuleMissingValue:
+		return "missing value"
+	case RuleClass:
+		return "value class"
+	case RuleNumber:
+		return "numeric value"
+	case RuleZeros:
+		return "leading zeros"
+	case RuleClassifier:
+		return "classifier"
+	}
+	return "unknown"
+}
+
+// Segment is a run of text followed by a value, as a string is split up when
+// comparing. The last segment of a string has no value.
+type Segment struct {
+	Text, Value string
+}
+
+// Explanation describes how two strings compare.
+type Explanation struct {
+	// Result is -1, 0 or 1 depending on if A sorts before, the same as or
+	// after B.
+	Result int
+	// Rule is the rule that decided the result.
+	Rule Rule
+	// Index is the segment that the rule was applied to.
+	Index int
+	// A and B are the segments of both strings.
+	A, B []Segment
+}
+
+// Explain returns how a and b compare as whole strings, including the
+// segments that they were split into and which rule decided the result.
+// Note: articles, paths and tie-breaks are not taken into account, so the
+// result can differ from Compare when those options are used.
+func (s *Sorter) Explain(a, b string) Explanation {
+	c, index, rule := s.decide(a, b)
+	return Explanation{
+		Result: c,
+		Rule:   rule,
+		Index:  index,
+		A:      s.segments(a),
+		B:      s.segments(b),
+	}
+}
+
+func (s *Sorter) segments(str string) []Segment {
+	var (
+		res []Segment
+		t   = s.tokenizer(str)
+	)
+	for {
+		c := t.next()
+		res = append(res, Segment{Text: c.text, Value: c.value})
+		if c.value == "" {
+			return res
+		}
+	}
+}
+
+// valueRule returns the rule that decided between two values that compared
+// differently.
+func (s *Sorter) valueRule(x, y chunk) Rule {
+	switch {
+	case x.class != y.class:
+		return RuleClass
+	case x.class != digits:
+		return RuleClassifier
+	case trimZeros(x.value) == trimZeros(y.value):
+		return RuleZeros
+	}
+	return RuleNumber
+}
@@ -0,0 +1,70 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		a, b   string
+		zeros  Zeros
+		result int
+		rule   Rule
+		index  int
+	}{
+		{"equal", "file10.txt", "file10.txt", ZerosShorterFirst, 0, RuleEqual, 1},
+		{"empty", "", "a", ZerosShorterFirst, -1, RuleEmpty, 0},
+		{"text", "a10", "b2", ZerosShorterFirst, -1, RuleText, 0},
+		{"trailing text", "a10.png", "a10.jpg", ZerosShorterFirst, 1, RuleText, 1},
+		{"missing value", "a", "a1", ZerosShorterFirst, 1, RuleMissingValue, 0},
+		{"number", "file9", "file10", ZerosShorterFirst, -1, RuleNumber, 0},
+		{"zeros", "file010", "file10", ZerosShorterFirst, 1, RuleZeros, 0},
+		{"zeros longer", "file010", "file10", ZerosLongerFirst, -1, RuleZeros, 0},
+		{"zeros equal", "file010", "file10", ZerosEqual, 0, RuleEqual, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewSorter(WithZeros(tc.zeros)).Explain(tc.a, tc.b)
+			if expected, actual := tc.result, e.Result; expected != actual {
+				t.Errorf("expected: %v, actual: %v", expected, actual)
+			}
+			if expected, actual := tc.rule, e.Rule; expected != actual {
+				t.Errorf("expected: %v, actual: %v", expected, actual)
+			}
+			if expected, actual := tc.index, e.Index; expected != actual {
+				t.Errorf("expected: %v, actual: %v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestExplainClassifiers(t *testing.T) {
+	t.Parallel()
+
+	sorter := NewSorter(WithUnits(), WithIPAddresses())
+
+	if expected, actual := RuleClassifier, sorter.Explain("512K", "2G").Rule; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+	if expected, actual := RuleClass, sorter.Explain("10.0.0.1", "2G").Rule; expected != actual {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestExplainSegments(t *testing.T) {
+	t.Parallel()
+
+	e := NewSorter().Explain("img12b3.png", "")
+	expected := []Segment{{"img", "12"}, {"b", "3"}, {".png", ""}}
+	if !reflect.DeepEqual(e.A, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, e.A)
+	}
+	if expected := []Segment{{"", ""}}; !reflect.DeepEqual(e.B, expected) {
+		t.Errorf("expected: %v, actual: %v", expected, e.B)
+	}
+}
@@ -0,0 +1,42 @@
+package natural
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:generate go run gen_fold_tables.go
+
+// foldAccents removes any accents and diacritics from s, so `résumé` becomes
+// `resume`. Combining marks are dropped, so strings that have already been
+// decomposed are folded the same way.
+func foldAccents(s string) string {
+	// Most strings are plain ASCII, so check before doing any work.
+	if isASCII(s) {
+		return s
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(s))
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if f, ok := foldTable[r]; ok {
+			buf.WriteString(f)
+			continue
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortAccentFolding(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		actual, expected []string
+	}{
+		{
+			"accents",
+			[]string{"Zoe", "Émile", "Eve"},
+			[]string{"Émile", "Eve", "Zoe"},
+		},
+		{
+			"accents break ties",
+			[]string{"resumes", "résumé", "resume"},
+			[]string{"resume", "résumé", "resumes"},
+		},
+		{
+			"decomposed",
+			[]string{"Zoe", "Émile"},
+			[]string{"Émile", "Zoe"},
+		},
+		{
+			"ligatures",
+			[]string{"Strasse", "Straße", "Strasbourg"},
+			[]string{"Strasbourg", "Strasse", "Straße"},
+		},
+		{
+			"with numbers",
+			[]string{"café 10", "cafe 9", "café 9"},
+			[]string{"cafe 9", "café 9", "café 10"},
+		},
+	}
+
+	sorter := NewSorter(WithAccentFolding())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sorter.Sort(tc.actual)
+
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestFoldAccents(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		actual, expected string
+	}{
+		{"ascii", "resume", "resume"},
+		{"latin", "résumé", "resume"},
+		{"vietnamese", "Tiếng Việt", "Tieng Viet"},
+		{"greek", "άλφα", "αλφα"},
+		{"combining marks", "ñ", "n"},
+		{"no decomposition", "Øresund", "Oresund"},
+		{"other scripts", "世界", "世界"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := foldAccents(tc.actual); actual != tc.expected {
+				t.Errorf("expected: %q, actual: %q", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package natural
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Sequence is a group of strings that only differ by their last number, such
+// as `frame_0001.exr` and `frame_0002.exr`.
+type Sequence struct {
+	// Prefix and Suffix are the text either side of the number.
+	Prefix, Suffix string
+
+	// Items are the strings in the sequence, in natural order.
+	Items []string
+
+	// First and Last are the smallest and largest numbers in the sequence.
+	First, Last int
+
+	// Missing are the numbers between First and Last that have no item.
+	Missing []Range
+
+	// Duplicates are the items that share a number with another item, in
+	// natural order.
+	Duplicates []string
+}
+
+// Range is an inclusive range of numbers.
+type Range struct {
+	From, To int
+}
+
+func (r Range) String() string {
+	if r.From == r.To {
+		return strconv.Itoa(r.From)
+	}
+	return strconv.Itoa(r.From) + "-" + strconv.Itoa(r.To)
+}
+
+// Gaps groups items by the text either side of their last number and reports
+// the numbers missing from each group, along with any duplicates. Sequences
+// are returned in natural order of their prefix and then suffix.
+// Note: items without a number, or with a number that doesn't fit in an int
+// are ignored.
+func Gaps(items []string) []Sequence {
+	type key struct {
+		prefix, suffix string
+	}
+	var (
+		keys   []key
+		groups = map[key][]string{}
+		values = map[string]int{}
+	)
+	for _, item := range items {
+		nums := numbers(item)
+		if len(nums) == 0 {
+			continue
+		}
+
+		n := nums[len(nums)-1]
+		value, err := strconv.Atoi(item[n.pos:n.end])
+		if err != nil {
+			continue
+		}
+		values[item] = value
+
+		k := key{item[:n.pos], item[n.end:]}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], item)
+	}
+
+	sort.Slice(keys, func(a, b int) bool {
+		if c := compare(keys[a].prefix, keys[b].prefix); c != 0 {
+			return c < 0
+		}
+		return compare(keys[a].suffix, keys[b].suffix) < 0
+	})
+
+	res := make([]Sequence, 0, len(keys))
+	for _, k := range keys {
+		items := groups[k]
+		Sort(items)
+
+		// Count how many items share each number.
+		counts := map[int]int{}
+		for _, item := range items {
+			counts[values[item]]++
+		}
+
+		seq := Sequence{
+			Prefix: k.prefix,
+			Suffix: k.suffix,
+			Items:  items,
+			First:  values[items[0]],
+			Last:   values[items[0]],
+		}
+		for _, item := range items {
+			value := values[item]
+			if value < seq.First {
+				seq.First = value
+			}
+			if value > seq.Last {
+				seq.Last = value
+			}
+			if counts[value] > 1 {
+				seq.Duplicates = append(seq.Duplicates, item)
+			}
+		}
+
+		// Walk the numbers in order, recording the ranges in between.
+		nums := make([]int, 0, len(counts))
+		for value := range counts {
+			nums = append(nums, value)
+		}
+		sort.Ints(nums)
+		for i := 1; i < len(nums); i++ {
+			if nums[i]-nums[i-1] > 1 {
+				seq.Missing = append(seq.Missing, Range{nums[i-1] + 1, nums[i] - 1})
+			}
+		}
+
+		res = append(res, seq)
+	}
+	return res
+}
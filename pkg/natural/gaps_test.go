@@ -0,0 +1,95 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGaps(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		items    []string
+		expected []Sequence
+	}{
+		{
+			"no gaps",
+			[]string{"frame_0002.exr", "frame_0001.exr", "frame_0003.exr"},
+			[]Sequence{
+				{
+					Prefix: "frame_",
+					Suffix: ".exr",
+					Items:  []string{"frame_0001.exr", "frame_0002.exr", "frame_0003.exr"},
+					First:  1,
+					Last:   3,
+				},
+			},
+		},
+		{
+			"missing",
+			[]string{"f1", "f2", "f6", "f8", "f9"},
+			[]Sequence{
+				{
+					Prefix:  "f",
+					Items:   []string{"f1", "f2", "f6", "f8", "f9"},
+					First:   1,
+					Last:    9,
+					Missing: []Range{{3, 5}, {7, 7}},
+				},
+			},
+		},
+		{
+			"duplicates",
+			[]string{"f1", "f02", "f2", "f3", "f3"},
+			[]Sequence{
+				{
+					Prefix:     "f",
+					Items:      []string{"f1", "f2", "f02", "f3", "f3"},
+					First:      1,
+					Last:       3,
+					Duplicates: []string{"f2", "f02", "f3", "f3"},
+				},
+			},
+		},
+		{
+			"groups",
+			[]string{"shot10_2.exr", "shot2_1.exr", "shot10_1.exr", "notes.txt"},
+			[]Sequence{
+				{
+					Prefix: "shot2_",
+					Suffix: ".exr",
+					Items:  []string{"shot2_1.exr"},
+					First:  1,
+					Last:   1,
+				},
+				{
+					Prefix: "shot10_",
+					Suffix: ".exr",
+					Items:  []string{"shot10_1.exr", "shot10_2.exr"},
+					First:  1,
+					Last:   2,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := Gaps(tc.items); !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRangeString(t *testing.T) {
+	t.Parallel()
+
+	if expected, actual := "7", (Range{7, 7}).String(); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+	if expected, actual := "3-5", (Range{3, 5}).String(); expected != actual {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
@@ -0,0 +1,109 @@
+//go:build ignore
+// +build ignore
+
+// This program generates fold_tables.go from the Unicode decompositions of
+// each letter. Run it with `go generate`.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// blocks are the Unicode blocks that are folded.
+var blocks = []struct {
+	name     string
+	from, to rune
+}{
+	{"Latin-1 Supplement", 0x00c0, 0x00ff},
+	{"Latin Extended-A", 0x0100, 0x017f},
+	{"Latin Extended-B", 0x0180, 0x024f},
+	{"Greek and Coptic", 0x0370, 0x03ff},
+	{"Latin Extended Additional", 0x1e00, 0x1eff},
+	{"Greek Extended", 0x1f00, 0x1fff},
+}
+
+// letters are the letters that have no decomposition, but are still folded.
+var letters = map[rune]string{
+	'Æ': "AE", 'Ð': "D", 'Ø': "O", 'Þ': "TH", 'ß': "ss", 'æ': "ae", 'ð': "d",
+	'ø': "o", 'þ': "th", 'Đ': "D", 'đ': "d", 'Ħ': "H", 'ħ': "h", 'ı': "i",
+	'Ŀ': "L", 'ŀ': "l", 'Ł': "L", 'ł': "l", 'Œ': "OE", 'œ': "oe", 'Ŧ': "T",
+	'ŧ': "t", 'ƀ': "b", 'ƚ': "l",
+}
+
+func main() {
+	var buf bytes.Buffer
+	buf.WriteString(`// Code generated by gen_fold_tables.go; DO NOT EDIT.
+
+package natural
+
+// foldTable maps letters with accents and diacritics to the letters they're
+// based on, along with a few letters that have no decomposition (` + "`ß`, `ø`" + `).
+// It's generated from the Unicode decompositions of each letter, so that
+// folding doesn't need any external tables.
+var foldTable = map[rune]string{
+`)
+	for _, block := range blocks {
+		fmt.Fprintf(&buf, "// %s\n", block.name)
+		var n int
+		for r := block.from; r <= block.to; r++ {
+			s, ok := fold(r)
+			if !ok {
+				continue
+			}
+			if n > 0 && n%8 == 0 {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(&buf, "%q: %q, ", r, s)
+			n++
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("fold_tables.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// fold returns the letters r is based on, by removing the combining marks
+// from its decomposition, or false if it isn't folded.
+func fold(r rune) (string, bool) {
+	if s, ok := letters[r]; ok {
+		return s, true
+	}
+	// Modifier letters (`ʹ`) are spacing marks rather than letters.
+	if !unicode.IsLetter(r) || unicode.Is(unicode.Lm, r) {
+		return "", false
+	}
+	base := strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(string(r)))
+	if base == "" || base == string(r) {
+		return "", false
+	}
+	// The base letters can fold further (`Ǣ` is based on `Æ`).
+	var folded strings.Builder
+	for _, b := range base {
+		if s, ok := fold(b); ok {
+			folded.WriteString(s)
+		} else {
+			folded.WriteRune(b)
+		}
+	}
+	return folded.String(), true
+}
@@ -0,0 +1,133 @@
+package natural
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ips compares IPv4 and IPv6 addresses, with an optional prefix length or
+// port, by their numeric address, then their prefix length and then their
+// port. IPv4 addresses are always placed before IPv6 addresses.
+type ips struct{}
+
+func (ips) Match(s string) int {
+	// Find the longest run of characters that could make up an address.
+	n := strings.IndexFunc(s, not(isAddress))
+	if n == -1 {
+		n = len(s)
+	}
+
+	// Addresses at the end of a sentence can be followed by a `.` or `:`, so
+	// give those back until there's an address. Compressed addresses can end
+	// in `:` themselves (`fe80::`), so check before giving each one back.
+	for ; n > 0; n-- {
+		if isIP(s[:n]) {
+			break
+		}
+		if _, ok := splitPort(s[:n]); ok {
+			return n
+		}
+		if c := s[n-1]; c != '.' && c != ':' {
+			return 0
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+
+	// Check to see if there is a prefix length.
+	if n < len(s) && s[n] == '/' {
+		if m := indexOfNonNumber(s[n+1:]); m != 0 {
+			if m == -1 {
+				m = len(s) - n - 1
+			}
+			if _, _, err := net.ParseCIDR(s[:n+1+m]); err == nil {
+				return n + 1 + m
+			}
+		}
+	}
+	return n
+}
+
+func (ips) Compare(a, b string) int {
+	xIP, xOnes, xPort := parseIP(a)
+	yIP, yOnes, yPort := parseIP(b)
+
+	xV4, yV4 := xIP.To4() != nil, yIP.To4() != nil
+	if xV4 && !yV4 {
+		return -1
+	} else if !xV4 && yV4 {
+		return 1
+	}
+
+	if c := bytes.Compare(xIP.To16(), yIP.To16()); c != 0 {
+		return c
+	}
+	if c := compareInts(xOnes, yOnes); c != 0 {
+		return c
+	}
+	if c := compareInts(xPort, yPort); c != 0 {
+		return c
+	}
+
+	// The addresses are the same (`::ffff:10.0.0.1` vs `10.0.0.1`), so
+	// fallback to how they were written.
+	return strings.Compare(a, b)
+}
+
+// parseIP returns the address, the prefix length and the port of s,
+// addresses without a prefix length are treated as having every bit set and
+// addresses without a port have a port of -1.
+func parseIP(s string) (net.IP, int, int) {
+	if ip, network, err := net.ParseCIDR(s); err == nil {
+		ones, _ := network.Mask.Size()
+		return ip, ones, -1
+	}
+
+	port := -1
+	if i, ok := splitPort(s); ok {
+		// Note: the only failure is a port that is out of range, in which
+		// case it's the largest int, which is what we want.
+		port, _ = strconv.Atoi(s[i+1:])
+		s = s[:i]
+	}
+
+	ip := net.ParseIP(s)
+	if ip.To4() != nil {
+		return ip, 8 * net.IPv4len, port
+	}
+	return ip, 8 * net.IPv6len, port
+}
+
+// isIP returns if s is an address, which has at least one digit so that a
+// run of colons (`::`) in text isn't seen as one.
+func isIP(s string) bool {
+	return strings.Trim(s, ".:") != "" && net.ParseIP(s) != nil
+}
+
+// splitPort returns the index of the `:` between an IPv4 address and its
+// port (`10.0.0.1:8080`), if s is one.
+// Note: IPv6 addresses need brackets around them to have a port, so they're
+// not split.
+func splitPort(s string) (int, bool) {
+	i := strings.LastIndexByte(s, ':')
+	if i <= 0 || i == len(s)-1 || strings.IndexByte(s[:i], ':') != -1 {
+		return 0, false
+	}
+	if indexOfNonNumber(s[i+1:]) != -1 {
+		return 0, false
+	}
+	if ip := net.ParseIP(s[:i]); ip == nil || ip.To4() == nil {
+		return 0, false
+	}
+	return i, true
+}
+
+func isAddress(r rune) bool {
+	return r == '.' || r == ':' ||
+		(r >= '0' && r <= '9') ||
+		(r >= 'a' && r <= 'f') ||
+		(r >= 'A' && r <= 'F')
+}
@@ -0,0 +1,105 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortIPAddresses(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		actual, expected []string
+	}{
+		{
+			"ipv4",
+			[]string{"10.0.0.10", "10.0.0.9", "9.255.255.255"},
+			[]string{"9.255.255.255", "10.0.0.9", "10.0.0.10"},
+		},
+		{
+			"ipv6",
+			[]string{"fe80::10", "fe80::9", "2001:db8::1", "::1"},
+			[]string{"::1", "2001:db8::1", "fe80::9", "fe80::10"},
+		},
+		{
+			"ipv4 before ipv6",
+			[]string{"fe80::1", "::1", "255.255.255.255"},
+			[]string{"255.255.255.255", "::1", "fe80::1"},
+		},
+		{
+			"prefixes",
+			[]string{"192.168.1.0/24", "192.168.1.0", "192.168.1.0/16", "192.168.0.0/24"},
+			[]string{"192.168.0.0/24", "192.168.1.0/16", "192.168.1.0/24", "192.168.1.0"},
+		},
+		{
+			"inside strings",
+			[]string{"host fe80::1 up", "host 10.0.0.10 up", "host 10.0.0.9 down", "host 10.0.0.9 up"},
+			[]string{"host 10.0.0.9 down", "host 10.0.0.9 up", "host 10.0.0.10 up", "host fe80::1 up"},
+		},
+		{
+			"end of sentence",
+			[]string{"at 10.0.0.10.", "at 10.0.0.9."},
+			[]string{"at 10.0.0.9.", "at 10.0.0.10."},
+		},
+		{
+			"compressed at the end",
+			[]string{"fe80::1", "fe80::", "fe80::/10"},
+			[]string{"fe80::/10", "fe80::", "fe80::1"},
+		},
+		{
+			"ports",
+			[]string{"10.0.0.1:8080", "10.0.0.10:80", "10.0.0.1:443", "10.0.0.1"},
+			[]string{"10.0.0.1", "10.0.0.1:443", "10.0.0.1:8080", "10.0.0.10:80"},
+		},
+		{
+			"port at the end of a sentence",
+			[]string{"on 10.0.0.1:8080.", "on 10.0.0.1:80:"},
+			[]string{"on 10.0.0.1:80:", "on 10.0.0.1:8080."},
+		},
+		{
+			"not addresses",
+			[]string{"v1.10", "v1.9", "a::b", "x:: 2", "x:: 10"},
+			[]string{"a::b", "v1.9", "v1.10", "x:: 2", "x:: 10"},
+		},
+	}
+
+	sorter := NewSorter(WithIPAddresses())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sorter.Sort(tc.actual)
+
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestMatchIPAddresses(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input    string
+		expected int
+	}{
+		{"fe80::", len("fe80::")},
+		{"fe80::.", len("fe80::")},
+		{"fe80:: up", len("fe80::")},
+		{"10.0.0.1:8080", len("10.0.0.1:8080")},
+		{"10.0.0.1:8080 up", len("10.0.0.1:8080")},
+		{"10.0.0.1:", len("10.0.0.1")},
+		{"10.0.0.1/8", len("10.0.0.1/8")},
+		{"::", 0},
+		{"fe80::1:8080", len("fe80::1:8080")},
+		{"1.2.3.4.5", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			if actual := (ips{}).Match(tc.input); actual != tc.expected {
+				t.Errorf("expected: %d, actual: %d", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -2,11 +2,9 @@ package natural
 
 import (
 	"sort"
-	"strconv"
 	"strings"
 	"unicode"
-
-	"github.com/pkg/errors"
+	"unicode/utf8"
 )
 
 // Sort sorts input strings into a more human representation, for example in
@@ -26,66 +24,201 @@ func (s slice) Swap(a, b int) {
 }
 
 func (s slice) Less(a, b int) bool {
-	return compare(s[a], s[b])
+	return compare(s[a], s[b]) < 0
+}
+
+// compare returns -1, 0 or 1 depending on if a sorts before, the same as or
+// after b, using the default natural ordering.
+func compare(a, b string) int {
+	return defaultSorter.Compare(a, b)
 }
 
-func compare(a, b string) bool {
+func (s *Sorter) compare(a, b string) int {
+	c, _, _ := s.decide(a, b)
+	return c
+}
+
+// decide compares a and b, returning the result along with the index of the
+// chunk that decided it and the rule that was used.
+func (s *Sorter) decide(a, b string) (int, int, Rule) {
 	// Quick check to see if the length of a is empty and b has a value or the
 	// inverse.
 	if aLen, bLen := len(a), len(b); aLen == 0 && bLen > 0 {
-		return true
+		return -1, 0, RuleEmpty
 	} else if bLen == 0 && aLen > 0 {
-		return false
+		return 1, 0, RuleEmpty
 	}
 
-	// Make sure we don't call mutations on the original
-	x, y := a[:], b[:]
-
-	// Strategy, walk through each segment and check against the other source.
-	// Note: that a segments are greedy, so `001` is a segment and will be
-	// converted to `1` using `strconv.Atoi`.
-	for {
-		// Check to see if the string contains digits at the start of it
-		xPos, yPos := indexOfNumber(x), indexOfNumber(y)
-		if xPos == -1 && yPos == -1 {
-			return x < y
-		} else if xPos == -1 && yPos >= 0 {
-			return false
-		} else if yPos == -1 {
-			return true
+	// Strategy, walk through each chunk and check against the other source.
+	// Note: that a chunk is a run of text followed by a value, values are
+	// greedy, so `001` is a value and will be compared as `1`.
+	x, y := s.tokenizer(a), s.tokenizer(b)
+	for i := 0; ; i++ {
+		xChunk, yChunk := x.next(), y.next()
+
+		// Check to see if the chunk contains a value at the end of it
+		xValue, yValue := xChunk.value != "", yChunk.value != ""
+		if !xValue && !yValue {
+			if c := s.compareText(xChunk.text, yChunk.text); c != 0 {
+				return c, i, RuleText
+			}
+			return 0, i, RuleEqual
+		} else if !xValue && yValue {
+			return 1, i, RuleMissingValue
+		} else if !yValue {
+			return -1, i, RuleMissingValue
 		}
 
-		// Compare actual segments (seg)
-		if xSeg, ySeg := x[:xPos], y[:yPos]; xSeg != ySeg {
-			return xSeg < ySeg
+		// Compare actual text segments
+		if c := s.compareText(xChunk.text, yChunk.text); c != 0 {
+			return c, i, RuleText
 		}
 
-		// Move on past the non-digit
-		x, y = x[xPos:], y[yPos:]
-		xPos, yPos = indexOfNonNumber(x), indexOfNonNumber(y)
-		if xPos == -1 {
-			xPos = len(x)
-		}
-		if yPos == -1 {
-			yPos = len(y)
+		// Compare the values
+		if c := s.compareValue(xChunk, yChunk); c != 0 {
+			return c, i, s.valueRule(xChunk, yChunk)
 		}
+	}
+}
+
+func (s *Sorter) compareText(a, b string) int {
+	if s.accents {
+		a, b = foldAccents(a), foldAccents(b)
+	}
+	if s.cases {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+	}
+	if s.punctuation != PunctuationExact {
+		a = foldPunctuation(a, s.punctuation, s.separators)
+		b = foldPunctuation(b, s.punctuation, s.separators)
+	}
+	return strings.Compare(a, b)
+}
+
+func (s *Sorter) compareValue(x, y chunk) int {
+	if x.class != y.class {
+		// Values that are recognised by different classifiers can't be
+		// compared directly, so rank them by the classifier that found them.
+		return compareInts(x.class, y.class)
+	}
+	if x.class == digits {
+		return compareNumbers(x.value, y.value, s.zeros)
+	}
+	return s.classifiers[x.class].Compare(x.value, y.value)
+}
+
+// compareNumbers compares two runs of digits by their value, using the policy
+// to order numbers that only differ by their leading zeros.
+// Note: the runs can be of any length, so rather than converting them into
+// integers, leading zeros are dropped and the remaining digits are compared.
+func compareNumbers(a, b string, policy Zeros) int {
+	x, y := trimZeros(a), trimZeros(b)
+	if c := compareInts(len(x), len(y)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(x, y); c != 0 {
+		return c
+	}
+
+	// Sometimes numbers are not the same `001` vs `1` so rank them
+	// accordingly. By default larger values (positions) will get put lastly.
+	switch policy {
+	case ZerosLongerFirst:
+		return compareInts(len(b), len(a))
+	case ZerosEqual:
+		return 0
+	}
+	return compareInts(len(a), len(b))
+}
 
-		// Attempt to grab the integer from the string
-		// Note: Decimal positioning, because `.` are treated above, then we can use
-		// the position of matching values to check for decimal precision.
-		if xNum, yNum := coerceToInt(x[:xPos]), coerceToInt(y[:yPos]); xNum != yNum {
-			return xNum < yNum
+func trimZeros(s string) string {
+	return strings.TrimLeft(s, "0")
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// digits is the class of a chunk value that is a plain run of digits, rather
+// than one recognised by a Classifier.
+const digits = -1
+
+// chunk is a run of text, followed by a value. The last chunk of a string
+// has no value.
+type chunk struct {
+	text, value string
+	class       int
+}
+
+type tokenizer struct {
+	s           string
+	classifiers []Classifier
+}
+
+func (s *Sorter) tokenizer(str string) tokenizer {
+	return tokenizer{str, s.classifiers}
+}
+
+// next returns the next chunk of the string, once the string is exhausted
+// an empty chunk is returned.
+func (t *tokenizer) next() chunk {
+	for i := 0; i < len(t.s); {
+		// Classifiers are only checked at the start of a word, that way `x2G`
+		// isn't seen as a size.
+		if atBoundary(t.s, i) {
+			for class, c := range t.classifiers {
+				// Lengths past the end of the string are ignored, rather
+				// than trusting the classifier.
+				if n := c.Match(t.s[i:]); n > 0 && n <= len(t.s)-i && atBoundary(t.s, i+n) {
+					return t.advance(i, n, class)
+				}
+			}
 		}
 
-		// Sometimes numbers are not the same `001` vs `1` so rank them
-		// accordingly. Larger values (positions) will get put lastly.
-		if xPos != yPos {
-			return yPos > xPos
+		r, size := utf8.DecodeRuneInString(t.s[i:])
+		if unicode.IsDigit(r) {
+			n := indexOfNonNumber(t.s[i:])
+			if n == -1 {
+				n = len(t.s) - i
+			}
+			return t.advance(i, n, digits)
 		}
+		i += size
+	}
+
+	c := chunk{text: t.s, class: digits}
+	t.s = ""
+	return c
+}
+
+func (t *tokenizer) advance(pos, n, class int) chunk {
+	c := chunk{
+		text:  t.s[:pos],
+		value: t.s[pos : pos+n],
+		class: class,
+	}
+	t.s = t.s[pos+n:]
+	return c
+}
 
-		// Continue onwards
-		x, y = x[xPos:], y[yPos:]
+// atBoundary returns if the position in s falls between two words, where a
+// word is a run of letters and digits.
+func atBoundary(s string, pos int) bool {
+	if pos == 0 || pos >= len(s) {
+		return true
 	}
+	before, _ := utf8.DecodeLastRuneInString(s[:pos])
+	after, _ := utf8.DecodeRuneInString(s[pos:])
+	return !isWord(before) || !isWord(after)
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
 func indexOfNumber(s string) int {
@@ -98,16 +231,6 @@ func indexOfNonNumber(s string) int {
 	return strings.IndexFunc(s, not(unicode.IsDigit))
 }
 
-// coerceToInt converts a string value to a integer
-// Note: this panic's because sorting doesn't understand failures.
-func coerceToInt(s string) int {
-	n, err := strconv.Atoi(s)
-	if err != nil {
-		panic(errors.Wrapf(err, "invalid number %q", s))
-	}
-	return n
-}
-
 func not(fn func(rune) bool) func(rune) bool {
 	return func(r rune) bool {
 		return !fn(r)
@@ -73,6 +73,11 @@ func TestSort(t *testing.T) {
 			[]string{"1.002", "1.001", "1.003"},
 			[]string{"1.001", "1.002", "1.003"},
 		},
+		{
+			"large numbers",
+			[]string{"a100000000000000000000", "a99999999999999999999"},
+			[]string{"a99999999999999999999", "a100000000000000000000"},
+		},
 	}
 
 	for _, tc := range testCases {
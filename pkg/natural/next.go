@@ -0,0 +1,128 @@
+package natural
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Next returns the successor of s, by incrementing the last number in it, so
+// `img_0099.png` becomes `img_0100.png` and `v1.9` becomes `v1.10`.
+// Zero-padding is kept where possible, the number only grows wider when it
+// no longer fits.
+func Next(s string) (string, error) {
+	return NextAt(s, -1)
+}
+
+// Prev returns the predecessor of s, by decrementing the last number in it,
+// so `img_0100.png` becomes `img_0099.png` and `v1.10` becomes `v1.9`.
+func Prev(s string) (string, error) {
+	return PrevAt(s, -1)
+}
+
+// NextAt returns the successor of s, by incrementing the number at the given
+// index. Negative indexes count back from the last number, so -1 is the last
+// number.
+func NextAt(s string, index int) (string, error) {
+	return step(s, index, increment)
+}
+
+// PrevAt returns the predecessor of s, by decrementing the number at the
+// given index. Negative indexes count back from the last number, so -1 is
+// the last number.
+func PrevAt(s string, index int) (string, error) {
+	return step(s, index, decrement)
+}
+
+// number is the position of a run of digits inside a string.
+type number struct {
+	pos, end int
+}
+
+// numbers returns the position of every run of digits in s, using the same
+// tokenizer as the default sorting.
+func numbers(s string) []number {
+	var (
+		res []number
+		pos int
+	)
+	t := defaultSorter.tokenizer(s)
+	for {
+		c := t.next()
+		if c.value == "" {
+			return res
+		}
+		pos += len(c.text)
+		res = append(res, number{pos, pos + len(c.value)})
+		pos += len(c.value)
+	}
+}
+
+func step(s string, index int, fn func(string) (string, error)) (string, error) {
+	nums := numbers(s)
+	i := index
+	if i < 0 {
+		i += len(nums)
+	}
+	if i < 0 || i >= len(nums) {
+		return "", errors.Errorf("no number found (s: %q, index: %d)", s, index)
+	}
+
+	n := nums[i]
+	value, err := fn(s[n.pos:n.end])
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid number (s: %q)", s)
+	}
+	return s[:n.pos] + value + s[n.end:], nil
+}
+
+// increment adds one to a run of digits, keeping the same width unless the
+// number carries past it.
+func increment(s string) (string, error) {
+	if !isASCIIDigits(s) {
+		return "", errors.Errorf("unsupported digits %q", s)
+	}
+
+	b := []byte(s)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] != '9' {
+			b[i]++
+			return string(b), nil
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b), nil
+}
+
+// decrement subtracts one from a run of digits. Zero-padded numbers keep
+// their width, where as numbers without any padding shrink when they need
+// to, so `10` becomes `9`, but `010` becomes `009`.
+func decrement(s string) (string, error) {
+	if !isASCIIDigits(s) {
+		return "", errors.Errorf("unsupported digits %q", s)
+	}
+	if trimZeros(s) == "" {
+		return "", errors.Errorf("no predecessor of %q", s)
+	}
+
+	b := []byte(s)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] != '0' {
+			b[i]--
+			break
+		}
+		b[i] = '9'
+	}
+
+	res := string(b)
+	if s[0] != '0' && len(res) > 1 {
+		if res = trimZeros(res); res == "" {
+			res = "0"
+		}
+	}
+	return res, nil
+}
+
+func isASCIIDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
@@ -0,0 +1,100 @@
+package natural
+
+import "testing"
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		index            int
+		actual, expected string
+	}{
+		{"padded", -1, "img_0099.png", "img_0100.png"},
+		{"version", -1, "v1.9", "v1.10"},
+		{"suffix", -1, "build-7", "build-8"},
+		{"carry within padding", -1, "frame_0999", "frame_1000"},
+		{"width growth", -1, "frame_9999", "frame_10000"},
+		{"first number", 0, "v1.9", "v2.9"},
+		{"second to last number", -2, "a1b2c3", "a1b3c3"},
+		{"large numbers", -1, "id99999999999999999999", "id100000000000000000000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := NextAt(tc.actual, tc.index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected: %q, actual: %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPrev(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		index            int
+		actual, expected string
+	}{
+		{"padded", -1, "img_0100.png", "img_0099.png"},
+		{"version", -1, "v1.10", "v1.9"},
+		{"suffix", -1, "build-8", "build-7"},
+		{"padded to zero", -1, "frame_0001", "frame_0000"},
+		{"unpadded to zero", -1, "frame_1", "frame_0"},
+		{"width shrink", -1, "frame_1000", "frame_999"},
+		{"first number", 0, "v2.9", "v1.9"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := PrevAt(tc.actual, tc.index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected: %q, actual: %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNextPrevErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no number", func(t *testing.T) {
+		if _, err := Next("abc"); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("index out of range", func(t *testing.T) {
+		if _, err := NextAt("a1", 1); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("no predecessor", func(t *testing.T) {
+		if _, err := Prev("frame_000"); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		next, err := Next("img_0099.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		prev, err := Prev(next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected, actual := "img_0099.png", prev; expected != actual {
+			t.Errorf("expected: %q, actual: %q", expected, actual)
+		}
+	})
+}
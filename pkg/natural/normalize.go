@@ -0,0 +1,73 @@
+package natural
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Normalize rewrites the items with enough zero-padding that sorting them
+// byte by byte (`ls`, S3 listings, SQL ORDER BY) gives the same order as
+// Sort. Each number is padded to the widest number found at the same
+// position across all of the items, so `a9b1` and `a10b20` become `a09b01`
+// and `a10b20`.
+// An error is returned when padding can't give the same order as Sort. Sort
+// places a number before anything else at the same place, so `a1` sorts
+// before `a` and `a b`, which byte by byte comparisons can't do however the
+// numbers are padded. Numbers that only differ by their leading zeros, such
+// as `1` and `01`, are also an error as they become the same once padded.
+func Normalize(items []string) ([]string, error) {
+	// Work out the widest number at each position.
+	var widths []int
+	for _, item := range items {
+		for i, n := range numbers(item) {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := n.end - n.pos; w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	res := make([]string, len(items))
+	for i, item := range items {
+		var (
+			buf  bytes.Buffer
+			last int
+		)
+		for j, n := range numbers(item) {
+			buf.WriteString(item[last:n.pos])
+			if w := n.end - n.pos; w < widths[j] {
+				buf.WriteString(strings.Repeat("0", widths[j]-w))
+			}
+			buf.WriteString(item[n.pos:n.end])
+			last = n.end
+		}
+		buf.WriteString(item[last:])
+		res[i] = buf.String()
+	}
+
+	// Check the padded items are in the same order byte by byte as the items
+	// are naturally.
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return compare(items[order[a]], items[order[b]]) < 0
+	})
+	for i := 1; i < len(order); i++ {
+		x, y := order[i-1], order[i]
+		switch {
+		case items[x] == items[y]:
+		case res[x] == res[y]:
+			return nil, errors.Errorf("items can't be told apart once padded (items: %q, %q, padded: %q)", items[x], items[y], res[x])
+		case res[x] > res[y]:
+			return nil, errors.Errorf("items can't be padded to sort byte by byte, as %q sorts before %q (padded: %q, %q)", items[x], items[y], res[x], res[y])
+		}
+	}
+	return res, nil
+}
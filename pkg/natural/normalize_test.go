@@ -0,0 +1,131 @@
+package natural
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		items    []string
+		expected []string
+	}{
+		{
+			"single position",
+			[]string{"img9.png", "img10.png", "img100.png"},
+			[]string{"img009.png", "img010.png", "img100.png"},
+		},
+		{
+			"several positions",
+			[]string{"a9b1", "a10b20"},
+			[]string{"a09b01", "a10b20"},
+		},
+		{
+			"differing number of positions",
+			[]string{"v1.2", "v1.10", "v10"},
+			[]string{"v01.02", "v01.10", "v10"},
+		},
+		{
+			"no numbers",
+			[]string{"a", "b"},
+			[]string{"a", "b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := Normalize(tc.items)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNormalizeMatchesSort(t *testing.T) {
+	t.Parallel()
+
+	items := []string{"frame_2.exr", "frame_10.exr", "frame_1.exr", "shot3_frame_100.exr", "shot20_frame_9.exr", "shot3_frame_20.exr"}
+	assertNormalizeMatchesSort(t, items)
+}
+
+func TestNormalizeMatchesSortRandom(t *testing.T) {
+	t.Parallel()
+
+	var (
+		rnd    = rand.New(rand.NewSource(1))
+		parts  = []string{"a", "b", "B", "_", ".", " ", "0", "1", "01", "9", "10", "007", "100"}
+		padded int
+	)
+	for i := 0; i < 1000; i++ {
+		items := make([]string, 1+rnd.Intn(6))
+		for j := range items {
+			for k := rnd.Intn(5); k >= 0; k-- {
+				items[j] += parts[rnd.Intn(len(parts))]
+			}
+		}
+		if _, err := Normalize(items); err != nil {
+			continue
+		}
+		padded++
+		assertNormalizeMatchesSort(t, items)
+	}
+	if padded == 0 {
+		t.Error("expected some items to be padded")
+	}
+}
+
+// assertNormalizeMatchesSort checks sorting the padded items byte by byte
+// gives the same order as padding the naturally sorted items.
+func assertNormalizeMatchesSort(t *testing.T, items []string) {
+	t.Helper()
+
+	lexical, err := Normalize(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(lexical)
+
+	sorted := append([]string(nil), items...)
+	Sort(sorted)
+	normalized, err := Normalize(sorted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(normalized, lexical) {
+		t.Errorf("expected: %q, actual: %q (items: %q)", normalized, lexical, items)
+	}
+}
+
+func TestNormalizeErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		items []string
+	}{
+		// Sort places the numbers first, which byte by byte can't.
+		{"missing numbers", []string{"a", "a1", "b", "b2"}},
+		{"text after the number", []string{"a b", "a1"}},
+		{"fewer numbers", []string{"v1", "v1.10", "v10.2"}},
+		{"leading zeros", []string{"1", "01"}},
+		{"padded to the same", []string{"a1b", "a01b", "a10b"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Normalize(tc.items); err == nil {
+				t.Errorf("expected error (items: %q)", tc.items)
+			}
+		})
+	}
+}
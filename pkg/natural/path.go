@@ -0,0 +1,48 @@
+package natural
+
+import "strings"
+
+// comparePaths compares a and b one path component at a time, so `dir2/z`
+// sorts before `dir10/a`. The extension of a file is only compared once the
+// rest of the name is the same.
+func (s *Sorter) comparePaths(a, b string) int {
+	x, y := strings.Split(a, s.pathSeparator), strings.Split(b, s.pathSeparator)
+	for i := 0; i < len(x) && i < len(y); i++ {
+		// Every component apart from the last is a directory.
+		xDir, yDir := i < len(x)-1, i < len(y)-1
+		if s.directoriesFirst && xDir != yDir {
+			if xDir {
+				return -1
+			}
+			return 1
+		}
+
+		xStem, xExt := x[i], ""
+		if !xDir {
+			xStem, xExt = splitExt(xStem)
+		}
+		yStem, yExt := y[i], ""
+		if !yDir {
+			yStem, yExt = splitExt(yStem)
+		}
+
+		if c := s.compare(xStem, yStem); c != 0 {
+			return c
+		}
+		if c := s.compare(xExt, yExt); c != 0 {
+			return c
+		}
+	}
+
+	// Parent directories are placed before their contents.
+	return compareInts(len(x), len(y))
+}
+
+// splitExt splits a file name into its stem and extension. Files that start
+// with a `.` and have no other extension, have no extension.
+func splitExt(name string) (string, string) {
+	if pos := strings.LastIndexByte(name, '.'); pos > 0 {
+		return name[:pos], name[pos+1:]
+	}
+	return name, ""
+}
@@ -0,0 +1,69 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortPaths(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		options          []Option
+		actual, expected []string
+	}{
+		{
+			"components",
+			[]Option{WithPaths("/")},
+			[]string{"dir10/a", "dir2/z", "dir/z", "dir-a/b"},
+			[]string{"dir2/z", "dir10/a", "dir/z", "dir-a/b"},
+		},
+		{
+			"parents before contents",
+			[]Option{WithPaths("/")},
+			[]string{"a/b/c", "a/b", "a"},
+			[]string{"a", "a/b", "a/b/c"},
+		},
+		{
+			"extensions",
+			[]Option{WithPaths("/")},
+			[]string{"a.10.txt", "a-1.txt", "a.9.txt", "a.txt", "a.md"},
+			[]string{"a-1.txt", "a.9.txt", "a.10.txt", "a.md", "a.txt"},
+		},
+		{
+			"dot files",
+			[]Option{WithPaths("/")},
+			[]string{".profile", ".bashrc"},
+			[]string{".bashrc", ".profile"},
+		},
+		{
+			"mixed directories and files",
+			[]Option{WithPaths("/")},
+			[]string{"b", "c/a", "a"},
+			[]string{"a", "b", "c/a"},
+		},
+		{
+			"directories first",
+			[]Option{WithPaths("/"), WithDirectoriesFirst()},
+			[]string{"b", "c/a", "a", "d/", "c/b/a"},
+			[]string{"c/b/a", "c/a", "d/", "a", "b"},
+		},
+		{
+			"windows separator",
+			[]Option{WithPaths(`\`)},
+			[]string{`dir10\a`, `dir2\z`},
+			[]string{`dir2\z`, `dir10\a`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			NewSorter(tc.options...).Sort(tc.actual)
+
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
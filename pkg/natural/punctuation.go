@@ -0,0 +1,68 @@
+package natural
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+)
+
+// Punctuation controls how runs of whitespace and punctuation are compared.
+type Punctuation int
+
+const (
+	// PunctuationExact compares whitespace and punctuation as it's written.
+	PunctuationExact Punctuation = iota
+
+	// PunctuationCollapse compares every run of whitespace and punctuation as
+	// a single space, so `Item-10`, `Item_10` and `Item  10` are the same.
+	PunctuationCollapse
+
+	// PunctuationIgnore drops whitespace and punctuation entirely, so
+	// `Item-10` and `Item10` are the same.
+	PunctuationIgnore
+)
+
+// DefaultPunctuation is the punctuation that's treated as a separator, along
+// with any whitespace.
+const DefaultPunctuation = "-_.,:;/"
+
+// ParsePunctuation returns the Punctuation for its name, which is one of
+// `exact`, `collapse` or `ignore`.
+func ParsePunctuation(name string) (Punctuation, bool) {
+	switch strings.ToLower(name) {
+	case "exact":
+		return PunctuationExact, true
+	case "collapse":
+		return PunctuationCollapse, true
+	case "ignore":
+		return PunctuationIgnore, true
+	}
+	return PunctuationExact, false
+}
+
+// foldPunctuation rewrites the runs of whitespace and punctuation in s.
+func foldPunctuation(s string, mode Punctuation, punctuation string) string {
+	isSeparator := func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(punctuation, r)
+	}
+	if mode == PunctuationExact || strings.IndexFunc(s, isSeparator) == -1 {
+		return s
+	}
+
+	var (
+		buf bytes.Buffer
+		run bool
+	)
+	for _, r := range s {
+		if !isSeparator(r) {
+			buf.WriteRune(r)
+			run = false
+			continue
+		}
+		if !run && mode == PunctuationCollapse {
+			buf.WriteRune(' ')
+		}
+		run = true
+	}
+	return buf.String()
+}
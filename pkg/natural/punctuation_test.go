@@ -0,0 +1,81 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortPunctuation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		mode             Punctuation
+		actual, expected []string
+	}{
+		{
+			"exact",
+			PunctuationExact,
+			[]string{"Item-10", "Item 10", "Item_9", "Item  11"},
+			[]string{"Item 10", "Item  11", "Item-10", "Item_9"},
+		},
+		{
+			"collapse",
+			PunctuationCollapse,
+			[]string{"Item-10", "Item 10", "Item_9", "Item  11"},
+			[]string{"Item_9", "Item 10", "Item-10", "Item  11"},
+		},
+		{
+			"collapse keeps separators apart",
+			PunctuationCollapse,
+			[]string{"Item10", "Item-9"},
+			[]string{"Item10", "Item-9"},
+		},
+		{
+			"ignore",
+			PunctuationIgnore,
+			[]string{"Item10", "Item-9", "Item_11"},
+			[]string{"Item-9", "Item10", "Item_11"},
+		},
+		{
+			"ignore ties",
+			PunctuationIgnore,
+			[]string{"a-b", "ab", "a b"},
+			[]string{"a b", "a-b", "ab"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			NewSorter(WithPunctuation(tc.mode, DefaultPunctuation)).Sort(tc.actual)
+
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestFoldPunctuation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		mode             Punctuation
+		actual, expected string
+	}{
+		{"exact", PunctuationExact, "a - b", "a - b"},
+		{"collapse", PunctuationCollapse, "a - b__c", "a b c"},
+		{"ignore", PunctuationIgnore, "a - b__c", "abc"},
+		{"other punctuation", PunctuationCollapse, "a+b", "a+b"},
+		{"tabs", PunctuationCollapse, "a\t\tb", "a b"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := foldPunctuation(tc.actual, tc.mode, DefaultPunctuation); actual != tc.expected {
+				t.Errorf("expected: %q, actual: %q", tc.expected, actual)
+			}
+		})
+	}
+}
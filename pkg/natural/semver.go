@@ -0,0 +1,104 @@
+package natural
+
+import (
+	"regexp"
+	"strings"
+)
+
+// semverPattern matches versions such as `1.2`, `v1.2.3`, `1.0.0-rc.1` and
+// `1.0.0+build.5`, capturing the core version and the pre-release. The
+// pre-release stops at the `+` of any build metadata. At least two parts are
+// needed, so plain numbers are left alone.
+var semverPattern = regexp.MustCompile(`^[vV]?(\d+(?:\.\d+)+)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?`)
+
+// semver compares versions by semantic versioning precedence, so `1.0.0-rc.2`
+// sorts before `1.0.0-rc.10`, which sorts before `1.0.0`. Versions with
+// fewer parts are treated as having zeros for the missing parts, so `1.2`
+// and `1.2.0` have the same precedence. Versions with the same precedence are
+// ordered by the zeros policy.
+type semver struct {
+	zeros Zeros
+}
+
+func (semver) Match(s string) int {
+	if loc := semverPattern.FindStringIndex(s); loc != nil {
+		return loc[1]
+	}
+	return 0
+}
+
+func (v semver) Compare(a, b string) int {
+	xCore, xPre := parseSemver(a)
+	yCore, yPre := parseSemver(b)
+
+	for i := 0; i < len(xCore) || i < len(yCore); i++ {
+		if c := compareNumbers(part(xCore, i), part(yCore, i), ZerosEqual); c != 0 {
+			return c
+		}
+	}
+
+	// Versions without a pre-release have a higher precedence.
+	if len(xPre) == 0 && len(yPre) > 0 {
+		return 1
+	} else if len(xPre) > 0 && len(yPre) == 0 {
+		return -1
+	}
+	for i := 0; i < len(xPre) && i < len(yPre); i++ {
+		if c := compareIdentifiers(xPre[i], yPre[i]); c != 0 {
+			return c
+		}
+	}
+	if c := compareInts(len(xPre), len(yPre)); c != 0 {
+		return c
+	}
+
+	// The versions have the same precedence (`1.2` vs `v1.2.0+build`), so
+	// they're equal when leading zeros are ignored, otherwise fallback to the
+	// zeros policy and then how they were written.
+	if v.zeros == ZerosEqual {
+		return 0
+	}
+	for i := 0; i < len(xCore) && i < len(yCore); i++ {
+		if c := compareNumbers(xCore[i], yCore[i], v.zeros); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// parseSemver splits a version into the parts of its core version and its
+// pre-release identifiers, any build metadata is dropped.
+func parseSemver(s string) ([]string, []string) {
+	match := semverPattern.FindStringSubmatch(s)
+	if match == nil {
+		return nil, nil
+	}
+
+	var pre []string
+	if match[2] != "" {
+		pre = strings.Split(match[2], ".")
+	}
+	return strings.Split(match[1], "."), pre
+}
+
+func part(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return "0"
+}
+
+// compareIdentifiers compares pre-release identifiers, numeric identifiers
+// are compared by value and sort before alphanumeric identifiers.
+func compareIdentifiers(a, b string) int {
+	xNum, yNum := isASCIIDigits(a), isASCIIDigits(b)
+	switch {
+	case xNum && yNum:
+		return compareNumbers(a, b, ZerosEqual)
+	case xNum:
+		return -1
+	case yNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
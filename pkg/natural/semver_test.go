@@ -0,0 +1,120 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortSemver(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		actual, expected []string
+	}{
+		{
+			"versions",
+			[]string{"1.10.0", "1.2.0", "1.9.1", "0.9"},
+			[]string{"0.9", "1.2.0", "1.9.1", "1.10.0"},
+		},
+		{
+			"pre-releases",
+			[]string{"1.0.0", "1.0.0-rc.10", "1.0.0-alpha", "1.0.0-rc.2", "1.0.0-alpha.1", "1.0.0-beta"},
+			[]string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta", "1.0.0-rc.2", "1.0.0-rc.10", "1.0.0"},
+		},
+		{
+			"numeric identifiers first",
+			[]string{"1.0.0-a", "1.0.0-2"},
+			[]string{"1.0.0-2", "1.0.0-a"},
+		},
+		{
+			"prefixes and build metadata",
+			[]string{"v1.10.0", "v1.2.0+build.5", "1.2.0"},
+			[]string{"1.2.0", "v1.2.0+build.5", "v1.10.0"},
+		},
+		{
+			"build metadata after a pre-release",
+			[]string{"1.0.0+rc-1", "1.0.0-rc.1+build-5", "1.0.0-rc.1", "1.0.0-rc.2"},
+			[]string{"1.0.0-rc.1", "1.0.0-rc.1+build-5", "1.0.0-rc.2", "1.0.0+rc-1"},
+		},
+		{
+			"missing parts",
+			[]string{"1.2.1", "1.2.0", "1.2"},
+			[]string{"1.2", "1.2.0", "1.2.1"},
+		},
+		{
+			"inside strings",
+			[]string{"app 2.0.0", "app 2.0.0-rc.1", "app 1.10.0"},
+			[]string{"app 1.10.0", "app 2.0.0-rc.1", "app 2.0.0"},
+		},
+	}
+
+	sorter := NewSorter(WithSemver())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sorter.Sort(tc.actual)
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %q, actual: %q", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestSortSemverZeros(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		policy           Zeros
+		actual, expected []string
+	}{
+		{"shorter first", ZerosShorterFirst, []string{"1.02", "1.2"}, []string{"1.2", "1.02"}},
+		{"longer first", ZerosLongerFirst, []string{"1.2", "1.02"}, []string{"1.02", "1.2"}},
+		{"equal keeps the order", ZerosEqual, []string{"1.2", "1.02", "v1.2.0+build"}, []string{"1.2", "1.02", "v1.2.0+build"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			NewSorter(WithSemver(), WithZeros(tc.policy)).Sort(tc.actual)
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %q, actual: %q", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestDedupeSemver(t *testing.T) {
+	t.Parallel()
+
+	// Versions with the same precedence are equal when leading zeros are.
+	actual := NewSorter(WithSemver(), WithZeros(ZerosEqual)).Dedupe([]string{"1.3", "v1.2.0+build", "1.2", "1.02.0", "1.3.0-rc.1"})
+	if expected := []string{"v1.2.0+build", "1.3.0-rc.1", "1.3"}; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %q, actual: %q", expected, actual)
+	}
+}
+
+func TestParseSemver(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input     string
+		core, pre []string
+	}{
+		{"1.2", []string{"1", "2"}, nil},
+		{"v1.0.0-rc.1", []string{"1", "0", "0"}, []string{"rc", "1"}},
+		{"1.0.0-rc.1+build-5", []string{"1", "0", "0"}, []string{"rc", "1"}},
+		{"1.0.0+build-5.x", []string{"1", "0", "0"}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			core, pre := parseSemver(tc.input)
+			if !reflect.DeepEqual(core, tc.core) {
+				t.Errorf("expected: %q, actual: %q", tc.core, core)
+			}
+			if !reflect.DeepEqual(pre, tc.pre) {
+				t.Errorf("expected: %q, actual: %q", tc.pre, pre)
+			}
+		})
+	}
+}
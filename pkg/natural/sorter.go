@@ -0,0 +1,221 @@
+package natural
+
+import "sort"
+
+var defaultSorter = NewSorter()
+
+// Sorter performs natural sorting, using options to change how the values
+// found in each string are compared.
+type Sorter struct {
+	classifiers      []Classifier
+	custom           []Classifier
+	units            bool
+	ips              bool
+	semver           bool
+	zeros            Zeros
+	pathSeparator    string
+	directoriesFirst bool
+	articles         []string
+	accents          bool
+	cases            bool
+	punctuation      Punctuation
+	separators       string
+
+	// tieBreak compares strings that are the same once accents, case and
+	// punctuation are ignored.
+	tieBreak *Sorter
+}
+
+// Option changes how a Sorter compares strings.
+type Option func(*Sorter)
+
+// NewSorter yields a Sorter with the given options applied, without any
+// options it sorts the same as Sort.
+func NewSorter(options ...Option) *Sorter {
+	s := &Sorter{}
+	for _, option := range options {
+		option(s)
+	}
+
+	// Addresses and versions are made up of numbers, so they're checked
+	// before any other classifier gets the chance to claim the first part of
+	// them, where as units will claim any number, so they're checked last.
+	if s.ips {
+		s.classifiers = append(s.classifiers, ips{})
+	}
+	if s.semver {
+		s.classifiers = append(s.classifiers, semver{s.zeros})
+	}
+	s.classifiers = append(s.classifiers, s.custom...)
+	if s.units {
+		s.classifiers = append(s.classifiers, units{s.zeros})
+	}
+
+	if s.accents || s.cases || s.punctuation != PunctuationExact {
+		tieBreak := *s
+		tieBreak.accents = false
+		tieBreak.cases = false
+		tieBreak.punctuation = PunctuationExact
+		s.tieBreak = &tieBreak
+	}
+	return s
+}
+
+// WithUnits makes the Sorter understand quantities, so `512K` sorts before
+// `2G` and `250ms` sorts before `1.2s`. See units for the suffixes that are
+// understood.
+func WithUnits() Option {
+	return func(s *Sorter) {
+		s.units = true
+	}
+}
+
+// WithClassifier makes the Sorter recognise a custom type of value, such as
+// ticket IDs (`PROJ-123`), which are then compared by the Classifier rather
+// than as text and digits. Classifiers are checked in the order they're given,
+// after IP addresses and before units.
+func WithClassifier(classifier Classifier) Option {
+	return func(s *Sorter) {
+		s.custom = append(s.custom, classifier)
+	}
+}
+
+// WithIPAddresses makes the Sorter understand IPv4 and IPv6 addresses and
+// prefixes, so `10.0.0.9` sorts before `10.0.0.10` and `192.168.1.0/24` sorts
+// before `fe80::1`.
+func WithIPAddresses() Option {
+	return func(s *Sorter) {
+		s.ips = true
+	}
+}
+
+// WithSemver makes the Sorter compare versions by semantic versioning
+// precedence, so `1.0.0-rc.2` sorts before `1.0.0-rc.10` and `1.0.0-rc.10`
+// sorts before `1.0.0`. Versions are checked after IP addresses.
+func WithSemver() Option {
+	return func(s *Sorter) {
+		s.semver = true
+	}
+}
+
+// WithZeros changes how the Sorter orders numbers that only differ by their
+// leading zeros, such as `1`, `01` and `001`.
+func WithZeros(policy Zeros) Option {
+	return func(s *Sorter) {
+		s.zeros = policy
+	}
+}
+
+// WithPaths makes the Sorter compare paths one component at a time, using
+// the separator to split each path into components.
+func WithPaths(separator string) Option {
+	return func(s *Sorter) {
+		s.pathSeparator = separator
+	}
+}
+
+// WithDirectoriesFirst places directories before files when comparing paths.
+// It has no effect unless WithPaths is also used.
+func WithDirectoriesFirst() Option {
+	return func(s *Sorter) {
+		s.directoriesFirst = true
+	}
+}
+
+// WithArticles makes the Sorter skip any of the leading articles (`The`, `A`)
+// when comparing, so `The Matrix 2` sorts with the other `M`s. Strings that
+// are the same without their articles are then compared in full.
+func WithArticles(articles ...string) Option {
+	return func(s *Sorter) {
+		s.articles = append(s.articles, articles...)
+	}
+}
+
+// WithAccentFolding makes the Sorter compare text without any accents or
+// diacritics, so `Émile` sorts before `Zoe`. Accents are then only used to
+// break ties, so `resume` sorts before `résumé`.
+func WithAccentFolding() Option {
+	return func(s *Sorter) {
+		s.accents = true
+	}
+}
+
+// WithCaseFolding makes the Sorter compare text without regard to case, so
+// `apple` sorts before `Banana`. Case is then only used to break ties, so
+// `Apple` sorts before `apple`.
+func WithCaseFolding() Option {
+	return func(s *Sorter) {
+		s.cases = true
+	}
+}
+
+// WithPunctuation changes how the Sorter compares runs of whitespace and the
+// given punctuation in text, so that `Item-10` and `Item 10` can sort
+// together. Strings that are then the same are compared as they're written.
+func WithPunctuation(mode Punctuation, punctuation string) Option {
+	return func(s *Sorter) {
+		s.punctuation = mode
+		s.separators = punctuation
+	}
+}
+
+// Sort sorts input strings naturally.
+// Note: strings that compare the same keep their original order.
+func (s *Sorter) Sort(input []string) {
+	sort.SliceStable(input, func(a, b int) bool {
+		return s.Less(input[a], input[b])
+	})
+}
+
+// Less returns if a sorts before b.
+func (s *Sorter) Less(a, b string) bool {
+	return s.Compare(a, b) < 0
+}
+
+// Equal returns if a and b are the same, once the options of the Sorter are
+// taken into account. With ZerosEqual, `1` and `01` are equal.
+func (s *Sorter) Equal(a, b string) bool {
+	return s.Compare(a, b) == 0
+}
+
+// Dedupe sorts the input and removes any strings that are equal to the one
+// before them, returning the unique strings. The first of each run of equal
+// strings is kept.
+func (s *Sorter) Dedupe(input []string) []string {
+	s.Sort(input)
+
+	var res []string
+	for i, v := range input {
+		if i > 0 && s.Equal(res[len(res)-1], v) {
+			continue
+		}
+		res = append(res, v)
+	}
+	return res
+}
+
+// Compare returns -1, 0 or 1 depending on if a sorts before, the same as or
+// after b.
+func (s *Sorter) Compare(a, b string) int {
+	if c := s.compareArticles(a, b); c != 0 || s.tieBreak == nil {
+		return c
+	}
+	return s.tieBreak.Compare(a, b)
+}
+
+func (s *Sorter) compareArticles(a, b string) int {
+	if len(s.articles) > 0 {
+		x, y := stripArticle(a, s.articles), stripArticle(b, s.articles)
+		if c := s.compareStrings(x, y); c != 0 {
+			return c
+		}
+	}
+	return s.compareStrings(a, b)
+}
+
+func (s *Sorter) compareStrings(a, b string) int {
+	if s.pathSeparator != "" {
+		return s.comparePaths(a, b)
+	}
+	return s.compare(a, b)
+}
@@ -0,0 +1,129 @@
+package natural
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// durationPattern matches Go style durations, including compound
+	// durations such as `1h30m`.
+	durationPattern = regexp.MustCompile(`^(?:\d+(?:\.\d+)?(?:ns|us|µs|μs|ms|h|m|s))+`)
+
+	// sizePattern matches sizes with either SI (`kB`, `MB`) or IEC (`KiB`,
+	// `Mi`) suffixes, along with the single letter suffixes of `du -h`.
+	sizePattern = regexp.MustCompile(`^\d+(?:\.\d+)?(?:[kKMGTPE]i?B?|B)`)
+
+	// barePattern matches numbers without a unit, which are compared against
+	// quantities in their base unit (bytes or seconds).
+	barePattern = regexp.MustCompile(`^\d+`)
+)
+
+type unitKind int
+
+const (
+	bare unitKind = iota
+	size
+	duration
+)
+
+// units compares quantities by their normalised magnitude.
+//
+// Single letter size suffixes (`K`, `M`, `G`) follow `du -h` and along with
+// IEC suffixes (`Ki`, `KiB`) are powers of 1024, where as SI suffixes (`kB`,
+// `MB`) are powers of 1000. Durations are parsed with time.ParseDuration, so
+// `m` is always minutes and `M` is always mega.
+type units struct {
+	zeros Zeros
+}
+
+func (units) Match(s string) int {
+	for _, pattern := range []*regexp.Regexp{
+		durationPattern,
+		sizePattern,
+		barePattern,
+	} {
+		if loc := pattern.FindStringIndex(s); loc != nil {
+			return loc[1]
+		}
+	}
+	return 0
+}
+
+func (u units) Compare(a, b string) int {
+	xKind, xMag := parseQuantity(a)
+	yKind, yMag := parseQuantity(b)
+
+	// Bare numbers are compared to sizes as a number of bytes, but durations
+	// are always placed after both, so the order is the same whichever values
+	// are compared.
+	if x, y := xKind == duration, yKind == duration; x != y {
+		if x {
+			return 1
+		}
+		return -1
+	}
+	if xMag < yMag {
+		return -1
+	} else if xMag > yMag {
+		return 1
+	}
+
+	// The magnitudes are the same (`1024K` vs `1M`), so fallback to how they
+	// were written, using the zeros policy for the numbers.
+	xNum, xSuffix := splitQuantity(a)
+	yNum, ySuffix := splitQuantity(b)
+	if c := strings.Compare(xSuffix, ySuffix); c != 0 {
+		return c
+	}
+	return compareNumbers(xNum, yNum, u.zeros)
+}
+
+func parseQuantity(s string) (unitKind, float64) {
+	if loc := durationPattern.FindStringIndex(s); loc != nil && loc[1] == len(s) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			// Overly large durations fail to parse, so treat them as the
+			// largest possible value.
+			return duration, math.Inf(1)
+		}
+		return duration, d.Seconds()
+	}
+
+	// Note: the only failure is a number that is out of range, in which case
+	// the magnitude is infinite, which is what we want.
+	num, suffix := splitQuantity(s)
+	n, _ := strconv.ParseFloat(num, 64)
+
+	if suffix == "" {
+		return bare, n
+	}
+	return size, n * sizeMultiplier(suffix)
+}
+
+// splitQuantity splits the leading number from the rest of the quantity.
+func splitQuantity(s string) (string, string) {
+	pos := strings.IndexFunc(s, func(r rune) bool {
+		return r != '.' && (r < '0' || r > '9')
+	})
+	if pos == -1 {
+		pos = len(s)
+	}
+	return s[:pos], s[pos:]
+}
+
+func sizeMultiplier(suffix string) float64 {
+	base := 1024.0
+	if strings.HasSuffix(suffix, "B") && !strings.HasSuffix(suffix, "iB") {
+		base = 1000
+	}
+
+	exp := strings.IndexByte("BKMGTPE", strings.ToUpper(suffix)[0])
+	if exp == -1 {
+		return 1
+	}
+	return math.Pow(base, float64(exp))
+}
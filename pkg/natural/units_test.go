@@ -0,0 +1,117 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortUnits(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		actual, expected []string
+	}{
+		{
+			"sizes",
+			[]string{"2G", "512K", "1.5M", "12K"},
+			[]string{"12K", "512K", "1.5M", "2G"},
+		},
+		{
+			"sizes with bare numbers",
+			[]string{"1K", "0", "4.0K", "512"},
+			[]string{"0", "512", "1K", "4.0K"},
+		},
+		{
+			"si and iec sizes",
+			[]string{"1MiB", "1MB", "999kB", "1000KiB"},
+			[]string{"999kB", "1MB", "1000KiB", "1MiB"},
+		},
+		{
+			"same magnitude",
+			[]string{"1M", "1024K"},
+			[]string{"1024K", "1M"},
+		},
+		{
+			"durations",
+			[]string{"3m", "1.2s", "250ms", "1h", "1h30m", "90s"},
+			[]string{"250ms", "1.2s", "90s", "3m", "1h", "1h30m"},
+		},
+		{
+			"sizes before durations",
+			[]string{"1s", "1K"},
+			[]string{"1K", "1s"},
+		},
+		{
+			"bare numbers before durations",
+			[]string{"1s", "500", "1K"},
+			[]string{"500", "1K", "1s"},
+		},
+		{
+			"same magnitude written differently",
+			[]string{"1M", "1024K", "01024K"},
+			[]string{"1024K", "01024K", "1M"},
+		},
+		{
+			"with text",
+			[]string{"file 2G", "file 512K", "dir 1K"},
+			[]string{"dir 1K", "file 512K", "file 2G"},
+		},
+		{
+			"not at the start of a word",
+			[]string{"x2G", "x512K"},
+			[]string{"x2G", "x512K"},
+		},
+		{
+			"not at the end of a word",
+			[]string{"3min", "20min"},
+			[]string{"3min", "20min"},
+		},
+		{
+			"numeric",
+			[]string{"001", "2", "30", "22", "0", "00", "3", "1"},
+			[]string{"0", "00", "1", "001", "2", "3", "22", "30"},
+		},
+	}
+
+	sorter := NewSorter(WithUnits())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sorter.Sort(tc.actual)
+
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestUnitsTransitive(t *testing.T) {
+	t.Parallel()
+
+	// Every ordering of the values must sort the same way, which is only the
+	// case when the comparison is transitive.
+	values := []string{"1s", "500", "1K", "2m", "0", "1024", "1Ki", "1kB"}
+	expected := []string{"0", "500", "1kB", "1024", "1K", "1Ki", "1s", "2m"}
+
+	u := units{}
+	for i := range values {
+		for j := range values {
+			for k := range values {
+				a, b, c := values[i], values[j], values[k]
+				if u.Compare(a, b) < 0 && u.Compare(b, c) < 0 && u.Compare(a, c) >= 0 {
+					t.Errorf("expected: %q < %q, as %q < %q < %q", a, c, a, b, c)
+				}
+			}
+		}
+	}
+
+	sorter := NewSorter(WithUnits())
+	for i := range values {
+		actual := append(append([]string{}, values[i:]...), values[:i]...)
+		sorter.Sort(actual)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package natural
+
+import "strings"
+
+// Zeros controls how numbers that only differ by their leading zeros, such
+// as `1`, `01` and `001`, are ordered.
+type Zeros int
+
+const (
+	// ZerosShorterFirst places the numbers with fewer leading zeros first,
+	// so `1` sorts before `01`. This is the default.
+	ZerosShorterFirst Zeros = iota
+
+	// ZerosLongerFirst places the numbers with more leading zeros first, so
+	// `01` sorts before `1`.
+	ZerosLongerFirst
+
+	// ZerosEqual treats the numbers as exactly the same, so `1` and `01` are
+	// equal and can be removed as duplicates.
+	ZerosEqual
+)
+
+// ParseZeros returns the Zeros policy for its name, which is one of
+// `shorter`, `longer` or `equal`.
+func ParseZeros(name string) (Zeros, bool) {
+	switch strings.ToLower(name) {
+	case "shorter":
+		return ZerosShorterFirst, true
+	case "longer":
+		return ZerosLongerFirst, true
+	case "equal":
+		return ZerosEqual, true
+	}
+	return ZerosShorterFirst, false
+}
@@ -0,0 +1,117 @@
+package natural
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortZeros(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		options          []Option
+		actual, expected []string
+	}{
+		{
+			"shorter first",
+			[]Option{WithZeros(ZerosShorterFirst)},
+			[]string{"001", "01", "1", "a01b", "a1c"},
+			[]string{"1", "01", "001", "a1c", "a01b"},
+		},
+		{
+			"longer first",
+			[]Option{WithZeros(ZerosLongerFirst)},
+			[]string{"1", "001", "01", "a1b", "a01c"},
+			[]string{"001", "01", "1", "a01c", "a1b"},
+		},
+		{
+			"equal keeps the original order",
+			[]Option{WithZeros(ZerosEqual)},
+			[]string{"01", "2", "1", "001", "a1c", "a01b"},
+			[]string{"01", "1", "001", "2", "a01b", "a1c"},
+		},
+		{
+			"equal with units",
+			[]Option{WithZeros(ZerosEqual), WithUnits()},
+			[]string{"01", "1", "001"},
+			[]string{"01", "1", "001"},
+		},
+		{
+			"longer first with units",
+			[]Option{WithZeros(ZerosLongerFirst), WithUnits()},
+			[]string{"1", "001", "01"},
+			[]string{"001", "01", "1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			NewSorter(tc.options...).Sort(tc.actual)
+
+			if !reflect.DeepEqual(tc.actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, tc.actual)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		policy   Zeros
+		a, b     string
+		expected bool
+	}{
+		{"same", ZerosShorterFirst, "a1", "a1", true},
+		{"shorter first", ZerosShorterFirst, "a1", "a01", false},
+		{"longer first", ZerosLongerFirst, "a1", "a01", false},
+		{"equal", ZerosEqual, "a1", "a001", true},
+		{"equal different values", ZerosEqual, "a1", "a2", false},
+		{"equal with units", ZerosEqual, "1K", "01K", true},
+		{"shorter first with units", ZerosShorterFirst, "1K", "01K", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := NewSorter(WithZeros(tc.policy), WithUnits()).Equal(tc.a, tc.b); actual != tc.expected {
+				t.Errorf("expected: %t, actual: %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		policy           Zeros
+		actual, expected []string
+	}{
+		{
+			"shorter first",
+			ZerosShorterFirst,
+			[]string{"a2", "a01", "a1", "a1"},
+			[]string{"a1", "a01", "a2"},
+		},
+		{
+			"equal",
+			ZerosEqual,
+			[]string{"a2", "a01", "a1", "a001", "a02"},
+			[]string{"a01", "a2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := NewSorter(WithZeros(tc.policy)).Dedupe(tc.actual)
+
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected: %v, actual: %v", tc.expected, actual)
+			}
+		})
+	}
+}